@@ -13,6 +13,8 @@ import (
 
 	"github.com/che-incubator/che-workspace-operator/pkg/apis"
 	"github.com/che-incubator/che-workspace-operator/pkg/controller"
+	"github.com/che-incubator/che-workspace-operator/pkg/webhook"
+	"github.com/che-incubator/che-workspace-operator/pkg/webhook/server"
 	"github.com/che-incubator/che-workspace-operator/version"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -95,6 +97,8 @@ func main() {
 		Namespace:          namespace,
 		MapperProvider:     restmapper.NewDynamicRESTMapper,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Port:               server.WebhookServerPort,
+		CertDir:            server.WebhookServerCertDir,
 	})
 	if err != nil {
 		log.Error(err, "")
@@ -115,6 +119,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup admission webhooks
+	if err := webhook.SetUpWebhooks(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	if err = serveCRMetrics(cfg); err != nil {
 		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
 	}
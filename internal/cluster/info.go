@@ -13,6 +13,7 @@
 package cluster
 
 import (
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -89,3 +90,53 @@ func IsWebhookConfigurationEnabled() (bool, error) {
 
 	return false, nil
 }
+
+//IsIngressV1Supported returns true if the cluster serves Ingresses from the networking.k8s.io/v1 API
+func IsIngressV1Supported() (bool, error) {
+	kubeCfg, err := config.GetConfig()
+	if err != nil {
+		return false, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeCfg)
+	if err != nil {
+		return false, err
+	}
+	resources, err := discoveryClient.ServerResourcesForGroupVersion("networking.k8s.io/v1")
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "ingresses" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+//IsIstioInstalled returns true if the cluster serves Istio Gateways, i.e. Istio's CRDs are installed
+func IsIstioInstalled() (bool, error) {
+	kubeCfg, err := config.GetConfig()
+	if err != nil {
+		return false, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeCfg)
+	if err != nil {
+		return false, err
+	}
+	resources, err := discoveryClient.ServerResourcesForGroupVersion("networking.istio.io/v1beta1")
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "gateways" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -8,15 +8,17 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
-func SortComponentsByType(components []v1alpha1.ComponentSpec) (dockerimage, plugin []v1alpha1.ComponentSpec, err error) {
+func SortComponentsByType(components []v1alpha1.ComponentSpec) (dockerimage, plugin, kubernetes []v1alpha1.ComponentSpec, err error) {
 	for _, component := range components {
 		switch component.Type {
 		case v1alpha1.Dockerimage:
 			dockerimage = append(dockerimage, component)
 		case v1alpha1.CheEditor, v1alpha1.ChePlugin:
 			plugin = append(plugin, component)
+		case v1alpha1.Kubernetes, v1alpha1.Openshift:
+			kubernetes = append(kubernetes, component)
 		default:
-			return nil, nil, fmt.Errorf("unsupported component type encountered: %s", component.Type)
+			return nil, nil, nil, fmt.Errorf("unsupported component type encountered: %s", component.Type)
 		}
 	}
 	return
@@ -101,8 +101,7 @@ func endpointsToContainerPorts(endpoints []v1alpha1.Endpoint) ([]corev1.Containe
 		containerPorts = append(containerPorts, corev1.ContainerPort{
 			Name:          common.EndpointName(endpoint.Name),
 			ContainerPort: int32(endpoint.Port),
-			//Protocol:      corev1.Protocol(endpoint.Attributes[v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE]),
-			Protocol: corev1.ProtocolTCP,
+			Protocol:      common.EndpointProtocol(endpoint),
 		})
 		containerEndpoints = append(containerEndpoints, int(endpoint.Port))
 	}
@@ -0,0 +1,345 @@
+package adaptor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	oAppsV1 "github.com/openshift/api/apps/v1"
+	routeV1 "github.com/openshift/api/route/v1"
+	"io"
+	"io/ioutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxRecipeSize is the maximum size in bytes of a recipe fetched from a component's reference
+const maxRecipeSize = 1 << 20
+
+var recipeClient = http.Client{
+	Timeout: 10 * time.Second,
+	// Redirects are only followed to allowed recipe hosts, as the reference itself is
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		if !config.ControllerCfg.IsRecipeHostAllowed(req.URL.Hostname()) {
+			return fmt.Errorf("redirect to '%s' is not to an allowed recipe host", req.URL.Host)
+		}
+		return nil
+	},
+}
+
+// recipeScheme contains the Kubernetes types, as well as the OpenShift types that may be used in openshift component
+// recipes
+var recipeScheme = runtime.NewScheme()
+var recipeCodecs = serializer.NewCodecFactory(recipeScheme)
+
+func init() {
+	utilruntime.Must(scheme.AddToScheme(recipeScheme))
+	utilruntime.Must(routeV1.AddToScheme(recipeScheme))
+	utilruntime.Must(oAppsV1.AddToScheme(recipeScheme))
+}
+
+// ResolveRecipeReferences downloads the recipes of kubernetes and openshift components that specify a reference
+// but no referenceContent, and stores them in the component's referenceContent. Recipes already resolved for the same
+// reference in resolvedComponents, e.g. in a previous reconcile, are reused, so that each reference is only fetched
+// once instead of on every reconcile.
+func ResolveRecipeReferences(devfileComponents, resolvedComponents []v1alpha1.ComponentSpec) error {
+	resolvedRecipes := map[string]*string{}
+	for _, resolvedComponent := range resolvedComponents {
+		if resolvedComponent.Reference != "" && resolvedComponent.ReferenceContent != nil {
+			resolvedRecipes[resolvedComponent.Reference] = resolvedComponent.ReferenceContent
+		}
+	}
+	for idx, devfileComponent := range devfileComponents {
+		if devfileComponent.Type != v1alpha1.Kubernetes && devfileComponent.Type != v1alpha1.Openshift {
+			continue
+		}
+		if devfileComponent.ReferenceContent != nil && *devfileComponent.ReferenceContent != "" {
+			continue
+		}
+		if content, ok := resolvedRecipes[devfileComponent.Reference]; ok {
+			devfileComponents[idx].ReferenceContent = content
+			continue
+		}
+		recipe, err := fetchRecipe(devfileComponent.Reference)
+		if err != nil {
+			return fmt.Errorf("failed to resolve reference of component %s: %w", devfileComponent.Alias, err)
+		}
+		content := string(recipe)
+		devfileComponents[idx].ReferenceContent = &content
+	}
+	return nil
+}
+
+// AdaptKubernetesComponents converts kubernetes and openshift devfile components into ComponentDescriptions.
+// Containers from Pods, Deployments and DeploymentConfigs in the component's recipe are merged into the workspace's
+// PodAdditions; remaining objects (Services, ConfigMaps, Secrets, Routes) are returned separately, to be created
+// alongside the workspace.
+func AdaptKubernetesComponents(workspaceId, namespace string, devfileComponents []v1alpha1.ComponentSpec, commands []v1alpha1.CommandSpec) ([]v1alpha1.ComponentDescription, []runtime.Object, error) {
+	var components []v1alpha1.ComponentDescription
+	var objects []runtime.Object
+	for _, devfileComponent := range devfileComponents {
+		if devfileComponent.Type != v1alpha1.Kubernetes && devfileComponent.Type != v1alpha1.Openshift {
+			return nil, nil, fmt.Errorf("cannot adapt non-kubernetes type component %s in kubernetes adaptor", devfileComponent.Alias)
+		}
+		component, componentObjects, err := adaptKubernetesComponent(workspaceId, namespace, devfileComponent, commands)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to process component %s: %w", devfileComponent.Alias, err)
+		}
+		components = append(components, component)
+		objects = append(objects, componentObjects...)
+	}
+	return components, objects, nil
+}
+
+func adaptKubernetesComponent(workspaceId, namespace string, devfileComponent v1alpha1.ComponentSpec, commands []v1alpha1.CommandSpec) (v1alpha1.ComponentDescription, []runtime.Object, error) {
+	recipe, err := getRecipeContent(devfileComponent)
+	if err != nil {
+		return v1alpha1.ComponentDescription{}, nil, err
+	}
+	recipeObjects, err := decodeRecipe(recipe)
+	if err != nil {
+		return v1alpha1.ComponentDescription{}, nil, err
+	}
+
+	podAdditions := v1alpha1.PodAdditions{}
+	containerDescriptions := map[string]v1alpha1.ContainerDescription{}
+	var otherObjects []runtime.Object
+	selector := labels.SelectorFromSet(devfileComponent.Selector)
+	for _, obj := range recipeObjects {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return v1alpha1.ComponentDescription{}, nil, err
+		}
+		if !selector.Matches(labels.Set(objMeta.GetLabels())) {
+			continue
+		}
+
+		switch typedObj := obj.(type) {
+		case *corev1.Pod:
+			err = addPodTemplate(workspaceId, devfileComponent, typedObj.ObjectMeta, typedObj.Spec, &podAdditions, containerDescriptions)
+		case *appsv1.Deployment:
+			err = addPodTemplate(workspaceId, devfileComponent, typedObj.Spec.Template.ObjectMeta, typedObj.Spec.Template.Spec, &podAdditions, containerDescriptions)
+		case *oAppsV1.DeploymentConfig:
+			if typedObj.Spec.Template == nil {
+				err = fmt.Errorf("DeploymentConfig %s in recipe has no pod template", typedObj.Name)
+				break
+			}
+			err = addPodTemplate(workspaceId, devfileComponent, typedObj.Spec.Template.ObjectMeta, typedObj.Spec.Template.Spec, &podAdditions, containerDescriptions)
+		case *corev1.Service:
+			// Pods from the recipe are merged into the workspace pod, whose labels set by the controller take precedence
+			// over the recipe's pod labels, so recipe Services have to select the workspace pod instead
+			if len(typedObj.Spec.Selector) > 0 {
+				typedObj.Spec.Selector = map[string]string{
+					config.WorkspaceIDLabel: workspaceId,
+				}
+			}
+			objMeta.SetNamespace(namespace)
+			otherObjects = append(otherObjects, obj)
+		case *corev1.ConfigMap, *corev1.Secret:
+			objMeta.SetNamespace(namespace)
+			otherObjects = append(otherObjects, obj)
+		case *routeV1.Route:
+			if !config.ControllerCfg.IsOpenShift() {
+				err = fmt.Errorf("Route %s in recipe is only supported on OpenShift", typedObj.Name)
+				break
+			}
+			objMeta.SetNamespace(namespace)
+			otherObjects = append(otherObjects, obj)
+		default:
+			err = fmt.Errorf("unsupported object of kind %s in recipe", obj.GetObjectKind().GroupVersionKind().Kind)
+		}
+		if err != nil {
+			return v1alpha1.ComponentDescription{}, nil, err
+		}
+	}
+
+	componentCommands := GetDockerfileComponentCommands(devfileComponent, commands)
+	for _, command := range componentCommands {
+		// Commands can only be attributed to a specific machine if the component contributes exactly one container
+		if len(podAdditions.Containers) == 1 {
+			command.Attributes[config.CommandMachineNameAttribute] = podAdditions.Containers[0].Name
+		} else {
+			delete(command.Attributes, config.CommandMachineNameAttribute)
+		}
+	}
+
+	component := v1alpha1.ComponentDescription{
+		Name:         devfileComponent.Alias,
+		PodAdditions: podAdditions,
+		ComponentMetadata: v1alpha1.ComponentMetadata{
+			Containers:                 containerDescriptions,
+			ContributedRuntimeCommands: componentCommands,
+			Endpoints:                  devfileComponent.Endpoints,
+		},
+	}
+	return component, otherObjects, nil
+}
+
+// addPodTemplate merges the containers, volumes, and metadata of a pod from a recipe into podAdditions, applying
+// devfile component settings (memory limit, env, mountSources) to each container.
+func addPodTemplate(
+	workspaceId string,
+	devfileComponent v1alpha1.ComponentSpec,
+	podMeta metav1.ObjectMeta,
+	podSpec corev1.PodSpec,
+	podAdditions *v1alpha1.PodAdditions,
+	containerDescriptions map[string]v1alpha1.ContainerDescription) error {
+
+	for _, container := range podSpec.Containers {
+		if _, ok := container.Resources.Limits[corev1.ResourceMemory]; !ok {
+			resources, err := adaptResourcesFromString(devfileComponent.MemoryLimit)
+			if err != nil {
+				return err
+			}
+			if container.Resources.Limits == nil {
+				container.Resources.Limits = corev1.ResourceList{}
+			}
+			container.Resources.Limits[corev1.ResourceMemory] = resources.Limits[corev1.ResourceMemory]
+		}
+		for _, devfileEnvVar := range devfileComponent.Env {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  devfileEnvVar.Name,
+				Value: strings.ReplaceAll(devfileEnvVar.Value, "$(CHE_PROJECTS_ROOT)", config.DefaultProjectsSourcesRoot),
+			})
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "CHE_MACHINE_NAME",
+			Value: container.Name,
+		})
+		if devfileComponent.MountSources {
+			container.VolumeMounts = append(container.VolumeMounts, GetProjectSourcesVolumeMount(workspaceId))
+		}
+
+		var ports []int
+		for _, port := range container.Ports {
+			ports = append(ports, int(port.ContainerPort))
+		}
+		containerDescriptions[container.Name] = v1alpha1.ContainerDescription{
+			Attributes: map[string]string{
+				config.RestApisContainerSourceAttribute: config.RestApisRecipeSourceContainerAttribute,
+			},
+			Ports: ports,
+		}
+		podAdditions.Containers = append(podAdditions.Containers, container)
+	}
+	podAdditions.InitContainers = append(podAdditions.InitContainers, podSpec.InitContainers...)
+	podAdditions.Volumes = append(podAdditions.Volumes, podSpec.Volumes...)
+	podAdditions.PullSecrets = append(podAdditions.PullSecrets, podSpec.ImagePullSecrets...)
+
+	// Recipe pod labels are added to the workspace pod, but do not override the labels set by the controller
+	for key, value := range podMeta.Labels {
+		if podAdditions.Labels == nil {
+			podAdditions.Labels = map[string]string{}
+		}
+		podAdditions.Labels[key] = value
+	}
+	for key, value := range podMeta.Annotations {
+		if podAdditions.Annotations == nil {
+			podAdditions.Annotations = map[string]string{}
+		}
+		podAdditions.Annotations[key] = value
+	}
+	return nil
+}
+
+// getRecipeContent returns the referenceContent of a component. References are not downloaded here; they are resolved
+// into referenceContent by ResolveRecipeReferences before the component is adapted.
+func getRecipeContent(devfileComponent v1alpha1.ComponentSpec) ([]byte, error) {
+	if devfileComponent.ReferenceContent != nil && *devfileComponent.ReferenceContent != "" {
+		return []byte(*devfileComponent.ReferenceContent), nil
+	}
+	if devfileComponent.Reference == "" {
+		return nil, fmt.Errorf("neither reference nor referenceContent is specified")
+	}
+	return nil, fmt.Errorf("reference '%s' has not been resolved", devfileComponent.Reference)
+}
+
+// fetchRecipe downloads the recipe at reference, which must be an absolute http or https URL on an allowed recipe host.
+// Recipes larger than maxRecipeSize are rejected.
+func fetchRecipe(reference string) ([]byte, error) {
+	if reference == "" {
+		return nil, fmt.Errorf("neither reference nor referenceContent is specified")
+	}
+	referenceURL, err := url.Parse(reference)
+	if err != nil || !referenceURL.IsAbs() || (referenceURL.Scheme != "http" && referenceURL.Scheme != "https") {
+		return nil, fmt.Errorf("reference '%s' is not an absolute http or https URL", reference)
+	}
+	if !config.ControllerCfg.IsRecipeHostAllowed(referenceURL.Hostname()) {
+		return nil, fmt.Errorf("reference '%s' is not on an allowed recipe host; inline the recipe in referenceContent instead", reference)
+	}
+
+	resp, err := recipeClient.Get(referenceURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch reference '%s': got status %d", reference, resp.StatusCode)
+	}
+	recipe, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRecipeSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(recipe) > maxRecipeSize {
+		return nil, fmt.Errorf("recipe at reference '%s' is larger than %d bytes", reference, maxRecipeSize)
+	}
+	return recipe, nil
+}
+
+// decodeRecipe decodes a (possibly multi-document) yaml or json recipe into Kubernetes and OpenShift objects. Lists are
+// expanded into their items.
+func decodeRecipe(recipe []byte) ([]runtime.Object, error) {
+	decoder := recipeCodecs.UniversalDeserializer()
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(recipe)))
+	var objects []runtime.Object
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		obj, _, err := decoder.Decode(document, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode recipe: %w", err)
+		}
+		if meta.IsListType(obj) {
+			items, err := meta.ExtractList(obj)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				if unknown, ok := item.(*runtime.Unknown); ok {
+					item, _, err = decoder.Decode(unknown.Raw, nil, nil)
+					if err != nil {
+						return nil, fmt.Errorf("failed to decode recipe: %w", err)
+					}
+				}
+				objects = append(objects, item)
+			}
+		} else {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 	routeV1 "github.com/openshift/api/route/v1"
 	templateV1 "github.com/openshift/api/template/v1"
+	userV1 "github.com/openshift/api/user/v1"
 )
 
 func init() {
@@ -14,6 +15,7 @@ func init() {
 		AddToSchemes = append(AddToSchemes,
 			routeV1.AddToScheme,
 			templateV1.AddToScheme,
+			userV1.AddToScheme,
 		)
 	}
 }
@@ -20,6 +20,8 @@ type WorkspaceComponentStatus struct {
 	Ready bool `json:"ready"`
 	// +listType=map +listMapKey=name
 	ComponentDescriptions []ComponentDescription `json:"componentDescriptions"`
+	// Message explains why the components could not be provisioned, if they failed
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
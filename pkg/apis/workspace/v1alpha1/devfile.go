@@ -39,8 +39,11 @@ type DevfileMeta struct {
 }
 
 type DevfileAttributes struct {
-	PersistVolumes bool `json:"persistVolumes,omitempty"`
-	EditorFree     bool `json:"editorFree,omitempty"`
+	// Whether workspace files should be persisted across restarts. If explicitly set to false, workspace storage is
+	// ephemeral regardless of the configured storage strategy.
+	PersistVolumes *bool  `json:"persistVolumes,omitempty"`
+	EditorFree     bool   `json:"editorFree,omitempty"`
+	StorageSize    string `json:"storageSize,omitempty"` // Size of the workspace's PVC when the per-workspace storage strategy is used, e.g. 5Gi
 }
 
 type ProjectSpec struct {
@@ -50,10 +53,22 @@ type ProjectSpec struct {
 
 // Describes the project's source - type and location
 type ProjectSourceSpec struct {
-	Location string `json:"location"` // Project's source location address. Should be URL for git and github located projects, or; file:// for zip.
-	Type     string `json:"type"`     // Project's source type.
+	Location   string            `json:"location"`             // Project's source location address. Should be URL for git and github located projects, or; file:// for zip.
+	Type       ProjectSourceType `json:"type"`                 // Project's source type.
+	Branch     string            `json:"branch,omitempty"`     // The name of the branch to check out after cloning the repository. Applicable only for git and github projects
+	StartPoint string            `json:"startPoint,omitempty"` // The tag or commit id to reset the checked out branch to. Applicable only for git and github projects
+	Tag        string            `json:"tag,omitempty"`        // The tag to check out after cloning the repository. Applicable only for git and github projects
+	CommitId   string            `json:"commitId,omitempty"`   // The id of the commit to check out after cloning the repository. Applicable only for git and github projects
 }
 
+type ProjectSourceType string
+
+const (
+	GitProjectSourceType    ProjectSourceType = "git"
+	GithubProjectSourceType ProjectSourceType = "github"
+	ZipProjectSourceType    ProjectSourceType = "zip"
+)
+
 type ComponentSpec struct {
 	//provision fields for all components types
 
@@ -109,6 +124,17 @@ const (
 	PROTOCOL_ENDPOINT_ATTRIBUTE EndpointAttribute = "protocol"
 
 	DISCOVERABLE_ATTRIBUTE EndpointAttribute = "discoverable"
+
+	//endpoint attribute that is used to configure the path of the endpoint's URL, e.g. /api
+	PATH_ENDPOINT_ATTRIBUTE EndpointAttribute = "path"
+
+	//endpoint attribute that is used to configure whether the endpoint is only exposed within the cluster, e.g. to
+	//other workspaces, rather than through an ingress or route
+	INTERNAL_ENDPOINT_ATTRIBUTE EndpointAttribute = "internal"
+
+	//prefix of endpoint attributes that set annotations on the endpoint's ingress, overriding the configured ones,
+	//e.g. ingress.annotation.nginx.ingress.kubernetes.io/proxy-body-size. An empty value removes the annotation
+	INGRESS_ANNOTATION_ENDPOINT_ATTRIBUTE_PREFIX = "ingress.annotation."
 )
 
 // Describes environment variable
@@ -12,6 +12,8 @@ type WorkspaceSpec struct {
 	Started bool `json:"started"`
 	// Routing class the defines how the workspace will be exposed to the external network
 	RoutingClass WorkspaceRoutingClass `json:"routingClass,omitempty"`
+	// Additional users and groups allowed to access the workspace's endpoints, e.g. for pair programming
+	Access WorkspaceAccess `json:"access,omitempty"`
 	// Workspace Structure defined in the Devfile format syntax.
 	// For more details see the Che 7 documentation: https://www.eclipse.org/che/docs/che-7/making-a-workspace-portable-using-a-devfile/
 	Devfile DevfileSpec `json:"devfile"`
@@ -24,7 +26,6 @@ type WorkspaceStatus struct {
 	Status      WorkspaceStatusType `json:"status,omitempty"`
 	// Conditions represent the latest available observations of an object's state
 	// +listType=map
-	// TODO: Handle conditions in general
 	Condition []WorkspaceCondition `json:"condition,omitempty"`
 
 	// TODO: This could potentially be handled via configmap more cleanly
@@ -38,7 +39,7 @@ type WorkspaceStatusAdditionalFields struct {
 // WorkspaceCondition contains details for the current condition of this workspace.
 type WorkspaceCondition struct {
 	// Type is the type of the condition.
-	Type string `json:"type"`
+	Type WorkspaceConditionType `json:"type"`
 	// Status is the status of the condition.
 	// Can be True, False, Unknown.
 	Status corev1.ConditionStatus `json:"status"`
@@ -50,6 +51,20 @@ type WorkspaceCondition struct {
 	Message string `json:"message,omitempty"`
 }
 
+// WorkspaceConditionType is the type of a WorkspaceCondition, corresponding to a stage in starting the workspace
+type WorkspaceConditionType string
+
+// Valid workspace condition types
+const (
+	WorkspaceConditionComponentsReady     WorkspaceConditionType = "ComponentsReady"
+	WorkspaceConditionRoutingReady        WorkspaceConditionType = "RoutingReady"
+	WorkspaceConditionServiceAccountReady WorkspaceConditionType = "ServiceAccountReady"
+	WorkspaceConditionRBACReady           WorkspaceConditionType = "RBACReady"
+	WorkspaceConditionStorageReady        WorkspaceConditionType = "StorageReady"
+	WorkspaceConditionDeploymentReady     WorkspaceConditionType = "DeploymentReady"
+	WorkspaceConditionStorageCleanedUp    WorkspaceConditionType = "StorageCleanedUp"
+)
+
 type WorkspaceStatusType string
 
 // Valid workspace Statuses
@@ -58,6 +73,8 @@ const (
 	WorkspaceStatusStarted  WorkspaceStatusType = "Started"
 	WorkspaceStatusStopped  WorkspaceStatusType = "Stopped"
 	WorkspaceStatusFailed   WorkspaceStatusType = "Failed"
+	// WorkspaceStatusTerminating means the workspace is being deleted and its resources are being cleaned up
+	WorkspaceStatusTerminating WorkspaceStatusType = "Terminating"
 )
 
 // WorkspacePhase is a label for the condition of a workspace at the current time.
@@ -69,6 +86,9 @@ type WorkspacePhase string
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=workspaces,scope=Namespaced
+// +kubebuilder:printcolumn:name="Workspace ID",type="string",JSONPath=".status.workspaceId",description="The workspace's unique id"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.status",description="The current workspace startup phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Workspace struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -12,6 +12,17 @@ type WorkspaceRoutingSpec struct {
 	IngressGlobalDomain string                `json:"ingressGlobalDomain"`
 	Endpoints           map[string][]Endpoint `json:"endpoints"`
 	PodSelector         map[string]string     `json:"podSelector"`
+	// Name of the user that created the workspace. If set, routing classes that authenticate users only admit the
+	// creator and the users and groups listed in access
+	Creator string `json:"creator,omitempty"`
+	// Additional users and groups allowed to access the workspace's endpoints, e.g. for pair programming
+	Access WorkspaceAccess `json:"access,omitempty"`
+}
+
+// WorkspaceAccess lists users and groups that are allowed to access a workspace in addition to its creator
+type WorkspaceAccess struct {
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
 }
 
 type WorkspaceRoutingClass string
@@ -19,20 +30,38 @@ type WorkspaceRoutingClass string
 const (
 	WorkspaceRoutingDefault        WorkspaceRoutingClass = ""
 	WorkspaceRoutingOpenShiftOauth WorkspaceRoutingClass = "openshift-oauth"
+	// WorkspaceRoutingPath exposes all endpoints on one host, under the path /<workspaceId>/<endpoint>/
+	WorkspaceRoutingPath WorkspaceRoutingClass = "path"
+	// WorkspaceRoutingIstio exposes endpoints through an Istio ingress gateway
+	WorkspaceRoutingIstio WorkspaceRoutingClass = "istio"
 )
 
 // WorkspaceRoutingStatus defines the observed state of WorkspaceRouting
 // +k8s:openapi-gen=true
 type WorkspaceRoutingStatus struct {
-	PodAdditions     *PodAdditions               `json:"podAdditions,omitempty"`
+	PodAdditions     *PodAdditions                `json:"podAdditions,omitempty"`
 	ExposedEndpoints map[string][]ExposedEndpoint `json:"exposedEndpoints,omitempty"`
-	Ready            bool                         `json:"ready"`
+	// Readiness of each exposed endpoint, by endpoint name
+	EndpointStatuses map[string]EndpointStatus `json:"endpointStatuses,omitempty"`
+	Ready            bool                      `json:"ready"`
+}
+
+// EndpointStatus describes whether the ingress or route for an exposed endpoint has been admitted
+type EndpointStatus struct {
+	Ready bool `json:"ready"`
+	// Brief CamelCase reason why the endpoint is not ready
+	Reason string `json:"reason,omitempty"`
+	// Human-readable details on why the endpoint is not ready
+	Message string `json:"message,omitempty"`
 }
 
 type ExposedEndpoint struct {
-	Name       string                       `json:"name"`
-	Url        string                       `json:"url"`
-	Attributes map[EndpointAttribute]string `json:"attributes"`
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	// URL of the endpoint within the cluster, through the workspace's internal service. Set for endpoints that can be
+	// reached without going through the public router.
+	InternalUrl string                       `json:"internalUrl,omitempty"`
+	Attributes  map[EndpointAttribute]string `json:"attributes"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -387,6 +387,11 @@ func (in *ContainerDescription) DeepCopy() *ContainerDescription {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileAttributes) DeepCopyInto(out *DevfileAttributes) {
 	*out = *in
+	if in.PersistVolumes != nil {
+		in, out := &in.PersistVolumes, &out.PersistVolumes
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -420,7 +425,7 @@ func (in *DevfileMeta) DeepCopy() *DevfileMeta {
 func (in *DevfileSpec) DeepCopyInto(out *DevfileSpec) {
 	*out = *in
 	out.DevfileMeta = in.DevfileMeta
-	out.DevfileAttributes = in.DevfileAttributes
+	in.DevfileAttributes.DeepCopyInto(&out.DevfileAttributes)
 	if in.Projects != nil {
 		in, out := &in.Projects, &out.Projects
 		*out = make([]ProjectSpec, len(*in))
@@ -476,6 +481,22 @@ func (in *Endpoint) DeepCopy() *Endpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Env) DeepCopyInto(out *Env) {
 	*out = *in
@@ -655,6 +676,32 @@ func (in *Workspace) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccess) DeepCopyInto(out *WorkspaceAccess) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccess.
+func (in *WorkspaceAccess) DeepCopy() *WorkspaceAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceComponentSpec) DeepCopyInto(out *WorkspaceComponentSpec) {
 	*out = *in
@@ -846,6 +893,7 @@ func (in *WorkspaceRoutingSpec) DeepCopyInto(out *WorkspaceRoutingSpec) {
 			(*out)[key] = val
 		}
 	}
+	in.Access.DeepCopyInto(&out.Access)
 	return
 }
 
@@ -884,6 +932,13 @@ func (in *WorkspaceRoutingStatus) DeepCopyInto(out *WorkspaceRoutingStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.EndpointStatuses != nil {
+		in, out := &in.EndpointStatuses, &out.EndpointStatuses
+		*out = make(map[string]EndpointStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -900,6 +955,7 @@ func (in *WorkspaceRoutingStatus) DeepCopy() *WorkspaceRoutingStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
+	in.Access.DeepCopyInto(&out.Access)
 	in.Devfile.DeepCopyInto(&out.Devfile)
 	return
 }
@@ -197,6 +197,13 @@ func schema_pkg_apis_workspace_v1alpha1_WorkspaceComponentStatus(ref common.Refe
 							},
 						},
 					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message explains why the components could not be provisioned, if they failed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"ready", "componentDescriptions"},
 			},
@@ -309,12 +316,25 @@ func schema_pkg_apis_workspace_v1alpha1_WorkspaceRoutingSpec(ref common.Referenc
 							},
 						},
 					},
+					"creator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the user that created the workspace. If set, routing classes that authenticate users only admit the creator and the users and groups listed in access",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"access": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Additional users and groups allowed to access the workspace's endpoints, e.g. for pair programming",
+							Ref:         ref("./pkg/apis/workspace/v1alpha1.WorkspaceAccess"),
+						},
+					},
 				},
 				Required: []string{"workspaceId", "ingressGlobalDomain", "endpoints", "podSelector"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/workspace/v1alpha1.Endpoint"},
+			"./pkg/apis/workspace/v1alpha1.Endpoint", "./pkg/apis/workspace/v1alpha1.WorkspaceAccess"},
 	}
 }
 
@@ -350,6 +370,20 @@ func schema_pkg_apis_workspace_v1alpha1_WorkspaceRoutingStatus(ref common.Refere
 							},
 						},
 					},
+					"endpointStatuses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Readiness of each exposed endpoint, by endpoint name",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/workspace/v1alpha1.EndpointStatus"),
+									},
+								},
+							},
+						},
+					},
 					"ready": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"boolean"},
@@ -361,7 +395,7 @@ func schema_pkg_apis_workspace_v1alpha1_WorkspaceRoutingStatus(ref common.Refere
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/workspace/v1alpha1.ExposedEndpoint", "./pkg/apis/workspace/v1alpha1.PodAdditions"},
+			"./pkg/apis/workspace/v1alpha1.EndpointStatus", "./pkg/apis/workspace/v1alpha1.ExposedEndpoint", "./pkg/apis/workspace/v1alpha1.PodAdditions"},
 	}
 }
 
@@ -386,6 +420,12 @@ func schema_pkg_apis_workspace_v1alpha1_WorkspaceSpec(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"access": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Additional users and groups allowed to access the workspace's endpoints, e.g. for pair programming",
+							Ref:         ref("./pkg/apis/workspace/v1alpha1.WorkspaceAccess"),
+						},
+					},
 					"devfile": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Workspace Structure defined in the Devfile format syntax. For more details see the Che 7 documentation: https://www.eclipse.org/che/docs/che-7/making-a-workspace-portable-using-a-devfile/",
@@ -397,7 +437,7 @@ func schema_pkg_apis_workspace_v1alpha1_WorkspaceSpec(ref common.ReferenceCallba
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/workspace/v1alpha1.DevfileSpec"},
+			"./pkg/apis/workspace/v1alpha1.DevfileSpec", "./pkg/apis/workspace/v1alpha1.WorkspaceAccess"},
 	}
 }
 
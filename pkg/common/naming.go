@@ -13,3 +13,8 @@ func EndpointName(endpointName string) string {
 	name = strings.Trim(name, "-")
 	return name
 }
+
+// ServiceName returns the name of the service that exposes a workspace's endpoints within the cluster
+func ServiceName(workspaceId string) string {
+	return "service-" + workspaceId
+}
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EndpointProtocol returns the transport protocol of an endpoint: UDP for endpoints with protocol udp, and TCP
+// otherwise
+func EndpointProtocol(endpoint v1alpha1.Endpoint) corev1.Protocol {
+	if endpoint.Attributes[v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE] == "udp" {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
+
+// IsHTTPEndpoint returns false for endpoints with protocol tcp or udp. Such endpoints cannot be exposed through
+// ingresses or routes.
+func IsHTTPEndpoint(endpoint v1alpha1.Endpoint) bool {
+	switch endpoint.Attributes[v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE] {
+	case "tcp", "udp":
+		return false
+	}
+	return true
+}
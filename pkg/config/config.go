@@ -14,6 +14,7 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/che-incubator/che-workspace-operator/internal/cluster"
 	"os"
@@ -78,6 +79,14 @@ func (wc *ControllerConfig) GetPVCStorageClassName() *string {
 	return wc.GetProperty(workspacePVCStorageClassName)
 }
 
+func (wc *ControllerConfig) GetPVCStorageSize() string {
+	return wc.GetPropertyOrDefault(workspacePVCStorageSize, PVCStorageSize)
+}
+
+func (wc *ControllerConfig) GetStorageStrategy() string {
+	return wc.GetPropertyOrDefault(workspaceStorageStrategy, defaultWorkspaceStorageStrategy)
+}
+
 func (wc *ControllerConfig) GetCheRestApisDockerImage() string {
 	return wc.GetPropertyOrDefault(serverImageName, defaultServerImageName)
 }
@@ -98,6 +107,147 @@ func (wc *ControllerConfig) GetPluginArtifactsBrokerImage() string {
 	return wc.GetPropertyOrDefault(pluginArtifactsBrokerImage, defaultPluginArtifactsBrokerImage)
 }
 
+func (wc *ControllerConfig) GetProjectClonerImage() string {
+	return wc.GetPropertyOrDefault(projectClonerImage, defaultProjectClonerImage)
+}
+
+// IsRecipeHostAllowed returns true if recipes referenced by kubernetes and openshift components may be fetched from host
+func (wc *ControllerConfig) IsRecipeHostAllowed(host string) bool {
+	for _, allowed := range strings.Split(wc.GetPropertyOrDefault(recipeAllowedHosts, defaultRecipeAllowedHosts), ",") {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wc *ControllerConfig) GetWorkspaceIdleTimeout() string {
+	return wc.GetPropertyOrDefault(workspaceIdleTimeout, defaultWorkspaceIdleTimeout)
+}
+
+func (wc *ControllerConfig) GetWorkspaceMaxRunTime() string {
+	return wc.GetPropertyOrDefault(workspaceMaxRunTime, defaultWorkspaceMaxRunTime)
+}
+
+// GetIngressClass returns the ingress class for ingresses of the given routing class
+func (wc *ControllerConfig) GetIngressClass(routingClass string) string {
+	if class := wc.GetProperty(routingClassProperty(ingressClass, routingClass)); class != nil {
+		return *class
+	}
+	return wc.GetPropertyOrDefault(ingressClass, defaultIngressClass)
+}
+
+// GetIngressAnnotations returns the annotations to add to ingresses of the given routing class. Annotations
+// configured for the routing class replace all others. Otherwise, the annotations configured for all ingresses are
+// used, on top of the routing class's default annotations, if any; these are only used as they are if neither is
+// configured.
+func (wc *ControllerConfig) GetIngressAnnotations(routingClass string) (map[string]string, error) {
+	property := routingClassProperty(ingressAnnotations, routingClass)
+	if annotationsJSON := wc.GetProperty(property); annotationsJSON != nil {
+		return parseIngressAnnotations(property, *annotationsJSON)
+	}
+
+	annotations := map[string]string{}
+	classDefaultJSON, hasClassDefault := defaultRoutingClassIngressAnnotations[routingClass]
+	if hasClassDefault {
+		classDefault, err := parseIngressAnnotations(property, classDefaultJSON)
+		if err != nil {
+			return nil, err
+		}
+		annotations = classDefault
+	}
+	if wc.GetProperty(ingressAnnotations) == nil && hasClassDefault {
+		return annotations, nil
+	}
+	global, err := parseIngressAnnotations(ingressAnnotations, wc.GetPropertyOrDefault(ingressAnnotations, defaultIngressAnnotations))
+	if err != nil {
+		return nil, err
+	}
+	for annotation, value := range global {
+		annotations[annotation] = value
+	}
+	return annotations, nil
+}
+
+func parseIngressAnnotations(property, annotationsJSON string) (map[string]string, error) {
+	annotations := map[string]string{}
+	err := json.Unmarshal([]byte(annotationsJSON), &annotations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for config property %s: %w", property, err)
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	return annotations, nil
+}
+
+// IsEndpointIngressAnnotationAllowed returns true if endpoints may override the ingress annotation through their
+// ingress annotation attributes
+func (wc *ControllerConfig) IsEndpointIngressAnnotationAllowed(annotation string) bool {
+	for _, allowed := range strings.Split(wc.GetPropertyOrDefault(ingressEndpointAnnotationAllowlist, defaultIngressEndpointAnnotationAllowlist), ",") {
+		if strings.TrimSpace(allowed) == annotation {
+			return true
+		}
+	}
+	return false
+}
+
+// routingClassProperty returns the name of a config property that overrides property for routingClass
+func routingClassProperty(property, routingClass string) string {
+	if routingClass == "" {
+		routingClass = defaultRoutingClassName
+	}
+	return property + "." + routingClass
+}
+
+func (wc *ControllerConfig) GetIngressTLSSecretName() string {
+	return wc.GetPropertyOrDefault(ingressTLSSecretName, defaultIngressTLSSecretName)
+}
+
+func (wc *ControllerConfig) GetIngressTLSCertManagerIssuer() string {
+	return wc.GetPropertyOrDefault(ingressTLSCertManagerIssuer, defaultIngressTLSCertManagerIssuer)
+}
+
+func (wc *ControllerConfig) GetIngressTLSCertManagerIssuerKind() string {
+	return wc.GetPropertyOrDefault(ingressTLSCertManagerIssuerKind, defaultIngressTLSCertManagerIssuerKind)
+}
+
+func (wc *ControllerConfig) GetRoutingExternalServiceType() string {
+	return wc.GetPropertyOrDefault(routingExternalServiceType, defaultRoutingExternalServiceType)
+}
+
+// GetRoutingExternalServiceNodeAddress returns the address of cluster nodes for NodePort services, or an empty string
+// if the ingress global domain should be used
+func (wc *ControllerConfig) GetRoutingExternalServiceNodeAddress() string {
+	return wc.GetPropertyOrDefault(routingExternalServiceNodeAddress, defaultRoutingExternalServiceNodeAddress)
+}
+
+// GetRoutingExternalClasses returns the routing classes that are reconciled by external controllers
+func (wc *ControllerConfig) GetRoutingExternalClasses() []string {
+	var routingClasses []string
+	for _, routingClass := range strings.Split(wc.GetPropertyOrDefault(routingExternalClasses, defaultRoutingExternalClasses), ",") {
+		if routingClass = strings.TrimSpace(routingClass); routingClass != "" {
+			routingClasses = append(routingClasses, routingClass)
+		}
+	}
+	return routingClasses
+}
+
+// GetIstioGatewaySelector returns the labels that select the Istio ingress gateway pods
+func (wc *ControllerConfig) GetIstioGatewaySelector() (map[string]string, error) {
+	selectorJSON := wc.GetPropertyOrDefault(istioGatewaySelector, defaultIstioGatewaySelector)
+	selector := map[string]string{}
+	err := json.Unmarshal([]byte(selectorJSON), &selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for config property %s: %w", istioGatewaySelector, err)
+	}
+	return selector, nil
+}
+
+func (wc *ControllerConfig) GetIstioAuthorizationClaim() string {
+	return wc.GetPropertyOrDefault(istioAuthorizationClaim, defaultIstioAuthorizationClaim)
+}
+
 func (wc *ControllerConfig) GetWebhooksEnabled() string {
 	return wc.GetPropertyOrDefault(webhooksEnabled, defaultWebhooksEnabled)
 }
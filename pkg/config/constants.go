@@ -31,6 +31,16 @@ const (
 	SidecarDefaultMemoryLimit = "128M"
 	PVCStorageSize            = "1Gi"
 
+	// CommonStorageStrategy stores the files of all workspaces in a namespace on one shared PVC
+	CommonStorageStrategy = "common"
+	// PerWorkspaceStorageStrategy creates a PVC for each workspace, removed along with the workspace
+	PerWorkspaceStorageStrategy = "per-workspace"
+	// EphemeralStorageStrategy stores workspace files in an emptyDir volume; files are lost when the workspace stops
+	EphemeralStorageStrategy = "ephemeral"
+	// WorkspaceStorageStrategyAnnotation records the storage strategy a workspace was first reconciled with, so that
+	// changing the configured strategy does not move existing workspaces to different storage
+	WorkspaceStorageStrategyAnnotation = "org.eclipse.che.workspace/storage-strategy"
+
 	//RuntimeAdditionalInfo is a key of workspaceStatus.AdditionalInfo where runtime info is stored
 	RuntimeAdditionalInfo = "org.eclipse.che.workspace/runtime"
 
@@ -43,10 +53,48 @@ const (
 	//WorkspaceNameLabel is label key to store workspace identifier
 	WorkspaceNameLabel = "che.workspace_name"
 
+	//WorkspaceNamespaceLabel is set to "true" on namespaces that contain workspaces. The pod exec webhook only applies
+	//to namespaces with this label
+	WorkspaceNamespaceLabel = "che.workspace_namespace"
+
 	//CheOriginalNameLabel is label key to original name
 	CheOriginalNameLabel = "che.original_name"
 
+	//WorkspaceCreatorAnnotation stores the username of the user that created a workspace. It is set by the mutating
+	//webhook on creation and can only be changed by the creator
 	WorkspaceCreatorAnnotation = "org.eclipse.che.workspace/creator"
+
+	//WorkspaceCreatorUIDAnnotation stores the UID of the user that created a workspace, if known
+	WorkspaceCreatorUIDAnnotation = "org.eclipse.che.workspace/creator-uid"
+
+	//WorkspaceSkipStorageCleanupAnnotation can be set to "true" on a workspace to delete it without removing its files
+	//from the common PVC, e.g. if the cleanup job keeps failing
+	WorkspaceSkipStorageCleanupAnnotation = "org.eclipse.che.workspace/skip-storage-cleanup"
+
+	//WorkspaceIdleTimeoutAnnotation overrides the configured idle timeout for a workspace, e.g. 30m. Zero disables it
+	WorkspaceIdleTimeoutAnnotation = "org.eclipse.che.workspace/idle-timeout"
+
+	//WorkspaceMaxRunTimeAnnotation overrides the configured maximum run time for a workspace, e.g. 8h. Zero disables it
+	WorkspaceMaxRunTimeAnnotation = "org.eclipse.che.workspace/max-run-time"
+
+	//WorkspaceLastActivityAnnotation stores the time of the last user activity in a workspace, in RFC3339 format.
+	//Clients signal activity by updating it; the idle timeout is measured from this time. See README.md
+	WorkspaceLastActivityAnnotation = "org.eclipse.che.workspace/last-activity"
+
+	//WorkspaceStoppedByAnnotation records why the controller stopped a workspace, e.g. idle-timeout or max-run-time.
+	//It is removed when the workspace is started again
+	WorkspaceStoppedByAnnotation = "org.eclipse.che.workspace/stopped-by"
+
+	//WorkspaceCookieSecretRotationAnnotation can be set on a workspace to rotate the cookie secret used by its oauth
+	//proxies. Any change in its value generates a new secret and restarts the workspace pod
+	WorkspaceCookieSecretRotationAnnotation = "org.eclipse.che.workspace/cookie-secret-rotation"
+
+	//PrerequisiteUnmanagedAnnotation can be set to "true" on a namespace prerequisite, e.g. the common PVC, to stop
+	//the controller from updating it. Unmanaged prerequisites are still created if they do not exist
+	PrerequisiteUnmanagedAnnotation = "org.eclipse.che.workspace/unmanaged"
+
+	//StorageCleanupFinalizer is set on workspaces that store files on the common PVC, to remove them on deletion
+	StorageCleanupFinalizer = "storage.workspace.che.eclipse.org"
 )
 
 // Constants for che-rest-apis
@@ -18,9 +18,110 @@ const (
 
 	workspacePVCStorageClassName = "pvc.storage_class.name"
 
+	//workspacePVCStorageSize config property handles the size of created workspace PVCs. Devfiles can override it for per-workspace PVCs
+	workspacePVCStorageSize = "pvc.storage.size"
+
+	//workspaceStorageStrategy config property handles how workspace files are stored: common, per-workspace or ephemeral
+	workspaceStorageStrategy        = "che.workspace.storage.strategy"
+	defaultWorkspaceStorageStrategy = CommonStorageStrategy
+
 	pluginArtifactsBrokerImage        = "che.workspace.plugin_broker.artifacts.image"
 	defaultPluginArtifactsBrokerImage = "quay.io/eclipse/che-plugin-artifacts-broker:v3.1.0"
 
+	projectClonerImage        = "che.workspace.project_cloner.image"
+	defaultProjectClonerImage = "alpine/git:v2.24.1"
+
+	//recipeAllowedHosts config property handles the comma-separated hosts that kubernetes and openshift components may
+	//reference recipes on. Empty disables fetching referenced recipes; such components must inline their recipe in
+	//referenceContent instead
+	recipeAllowedHosts        = "che.workspace.recipe.allowed_hosts"
+	defaultRecipeAllowedHosts = ""
+
+	//workspaceIdleTimeout config property handles the default duration of inactivity after which workspaces are stopped,
+	//e.g. 30m. Empty or zero disables the idle timeout. Inactivity is measured from the workspace's last-activity
+	//annotation, which the operator does not update itself; only enable this if a client reports workspace activity
+	workspaceIdleTimeout        = "che.workspace.idle_timeout"
+	defaultWorkspaceIdleTimeout = ""
+
+	//workspaceMaxRunTime config property handles the default duration after which running workspaces are stopped
+	//regardless of activity, e.g. 8h. Empty or zero disables the limit
+	workspaceMaxRunTime        = "che.workspace.max_run_time"
+	defaultWorkspaceMaxRunTime = ""
+
+	//ingressClass config property handles the ingress class used for workspace ingresses. It is set as spec.ingressClassName
+	//on networking.k8s.io/v1 ingresses and as the kubernetes.io/ingress.class annotation on older ingresses. It can be
+	//overridden per routing class with ingress.class.<routing class>, e.g. ingress.class.path
+	ingressClass        = "ingress.class"
+	defaultIngressClass = "nginx"
+
+	//ingressAnnotations config property handles the annotations added to workspace ingresses, as a JSON object. If
+	//set, its annotations take precedence over the built-in defaults of routing classes. It can be overridden per
+	//routing class with ingress.annotations.<routing class>, e.g. ingress.annotations.path
+	ingressAnnotations        = "ingress.annotations"
+	defaultIngressAnnotations = `{"nginx.ingress.kubernetes.io/rewrite-target": "/", "nginx.ingress.kubernetes.io/ssl-redirect": "false"}`
+
+	//defaultRoutingClassName is the name of the default routing class in per-routing class config properties
+	defaultRoutingClassName = "basic"
+
+	//ingressEndpointAnnotationAllowlist config property handles the comma-separated ingress annotations that endpoints
+	//may override through ingress.annotation.<annotation> attributes. Other annotations are not accepted from
+	//endpoints, as some, e.g. nginx configuration snippets, would let workspace users configure the ingress controller
+	ingressEndpointAnnotationAllowlist        = "ingress.endpoint_annotation_allowlist"
+	defaultIngressEndpointAnnotationAllowlist = "nginx.ingress.kubernetes.io/proxy-read-timeout,nginx.ingress.kubernetes.io/proxy-send-timeout,nginx.ingress.kubernetes.io/proxy-body-size"
+
+	//ingressTLSSecretName config property handles the name of a Secret containing a wildcard certificate for
+	//ingress.global.domain. If set, ingresses for secure endpoints use it for TLS. The Secret must exist in each
+	//namespace where workspaces run
+	ingressTLSSecretName        = "ingress.tls.secret_name"
+	defaultIngressTLSSecretName = ""
+
+	//ingressTLSCertManagerIssuer config property handles the name of the cert-manager issuer used to request a
+	//certificate for each secure endpoint's host. It is only used if ingress.tls.secret_name is not set
+	ingressTLSCertManagerIssuer        = "ingress.tls.cert_manager.issuer"
+	defaultIngressTLSCertManagerIssuer = ""
+
+	//ingressTLSCertManagerIssuerKind config property handles the kind of the cert-manager issuer: ClusterIssuer or Issuer
+	ingressTLSCertManagerIssuerKind        = "ingress.tls.cert_manager.issuer_kind"
+	defaultIngressTLSCertManagerIssuerKind = "ClusterIssuer"
+
+	//routingExternalClasses config property handles a comma-separated list of routing classes that are reconciled by
+	//external controllers. Workspaces may only use these and the routing classes with a built-in solver
+	routingExternalClasses        = "routing.external_classes"
+	defaultRoutingExternalClasses = ""
+
+	//istioGatewaySelector config property handles the labels, as a JSON object, that select the Istio ingress gateway
+	//pods used by Gateways of the istio routing class
+	istioGatewaySelector        = "istio.gateway.selector"
+	defaultIstioGatewaySelector = `{"istio": "ingressgateway"}`
+
+	//istioAuthorizationClaim config property handles the JWT claim containing the username of requests to the mesh,
+	//e.g. preferred_username. If set, the istio routing class restricts access to the workspace ports exposed through
+	//the gateway to the workspace creator and allowed users. Requires a RequestAuthentication that validates tokens
+	//for workspace pods
+	istioAuthorizationClaim        = "istio.authorization.claim"
+	defaultIstioAuthorizationClaim = ""
+
+	//routingExternalServiceType config property handles the type of the services that expose tcp and udp endpoints,
+	//which cannot be exposed through ingresses or routes: LoadBalancer or NodePort. Endpoints exposed through
+	//LoadBalancer services are not ready until the cluster assigns the service an address, which requires a load
+	//balancer implementation
+	routingExternalServiceType        = "routing.external_service.type"
+	defaultRoutingExternalServiceType = "NodePort"
+
+	//routingExternalServiceNodeAddress config property handles the host name or IP address at which cluster nodes are
+	//reachable, used in the URLs of endpoints exposed through NodePort services. Defaults to the ingress global domain
+	routingExternalServiceNodeAddress        = "routing.external_service.node_address"
+	defaultRoutingExternalServiceNodeAddress = ""
+
 	webhooksEnabled = "che.webhooks.enabled"
 	defaultWebhooksEnabled = "true"
 )
+
+//defaultRoutingClassIngressAnnotations are the default ingress annotations for routing classes that require
+//different annotations than ingress.annotations. Annotations set in ingress.annotations take precedence, so it should
+//not set annotations required by these routing classes, e.g. rewrite-target, unless ingress.annotations.<routing
+//class> is also set
+var defaultRoutingClassIngressAnnotations = map[string]string{
+	//the path routing class strips the second capture group of its ingress paths, i.e. /<workspace>/<endpoint>
+	"path": `{"nginx.ingress.kubernetes.io/rewrite-target": "/$2", "nginx.ingress.kubernetes.io/use-regex": "true", "nginx.ingress.kubernetes.io/ssl-redirect": "false"}`,
+}
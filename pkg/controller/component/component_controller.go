@@ -8,6 +8,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -95,7 +97,7 @@ func (r *ReconcileComponent) Reconcile(request reconcile.Request) (reconcile.Res
 	}
 
 	var components []workspacev1alpha1.ComponentDescription
-	dockerimageDevfileComponents, pluginDevfileComponents, err := adaptor.SortComponentsByType(instance.Spec.Components)
+	dockerimageDevfileComponents, pluginDevfileComponents, kubernetesDevfileComponents, err := adaptor.SortComponentsByType(instance.Spec.Components)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -116,6 +118,13 @@ func (r *ReconcileComponent) Reconcile(request reconcile.Request) (reconcile.Res
 	}
 	components = append(components, pluginComponents...)
 
+	kubernetesComponents, kubernetesObjects, err := adaptor.AdaptKubernetesComponents(instance.Spec.WorkspaceId, instance.Namespace, kubernetesDevfileComponents, commands)
+	if err != nil {
+		reqLogger.Info("Failed to adapt kubernetes components")
+		return reconcile.Result{}, r.reconcileFailure(instance, err.Error())
+	}
+	components = append(components, kubernetesComponents...)
+
 	if brokerConfigMap != nil {
 		// TODO: Broker CM will not be deleted if it's no longer needed while workspace is running
 		reqLogger.Info("Reconciling broker ConfigMap")
@@ -128,9 +137,87 @@ func (r *ReconcileComponent) Reconcile(request reconcile.Request) (reconcile.Res
 		}
 	}
 
+	if len(kubernetesObjects) > 0 {
+		ok, err := r.reconcileRecipeObjects(instance, kubernetesObjects, reqLogger)
+		if conflictErr, isConflict := err.(*recipeObjectConflictError); isConflict {
+			reqLogger.Info("Recipe object belongs to another workspace", "name", conflictErr.name)
+			return reconcile.Result{}, r.reconcileFailure(instance, conflictErr.Error())
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !ok {
+			return reconcile.Result{Requeue: true}, nil
+		}
+	}
+
 	return reconcile.Result{}, r.reconcileStatus(instance, components)
 }
 
+// recipeObjectConflictError is returned when an object from a component recipe already exists in the namespace but
+// is not owned by the component's workspace, e.g. as another workspace in the namespace uses the same recipe
+type recipeObjectConflictError struct {
+	kind string
+	name string
+}
+
+func (e *recipeObjectConflictError) Error() string {
+	return fmt.Sprintf("%s %s from the component recipe already exists in the namespace and belongs to another workspace", e.kind, e.name)
+}
+
+// reconcileRecipeObjects creates objects defined in kubernetes/openshift component recipes if they do not exist yet.
+// Objects are owned by the workspace that owns the component, so that they are removed along with it. Existing objects
+// are not updated, as they may be modified while the workspace is running (e.g. generated Secrets). If an object with
+// the same name exists but is owned by something else, a recipeObjectConflictError is returned.
+func (r *ReconcileComponent) reconcileRecipeObjects(instance *workspacev1alpha1.Component, objects []runtime.Object, log logr.Logger) (ok bool, err error) {
+	ok = true
+	for _, obj := range objects {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return false, err
+		}
+		if workspaceRef := metav1.GetControllerOf(instance); workspaceRef != nil {
+			objMeta.SetOwnerReferences([]metav1.OwnerReference{*workspaceRef})
+		} else {
+			err = controllerutil.SetControllerReference(instance, objMeta, r.scheme)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		clusterObj := obj.DeepCopyObject()
+		namespacedName := types.NamespacedName{
+			Namespace: objMeta.GetNamespace(),
+			Name:      objMeta.GetName(),
+		}
+		err = r.client.Get(context.TODO(), namespacedName, clusterObj)
+		if err == nil {
+			clusterMeta, err := meta.Accessor(clusterObj)
+			if err != nil {
+				return false, err
+			}
+			owner := metav1.GetControllerOf(clusterMeta)
+			if owner == nil || owner.UID != objMeta.GetOwnerReferences()[0].UID {
+				return false, &recipeObjectConflictError{
+					kind: obj.GetObjectKind().GroupVersionKind().Kind,
+					name: objMeta.GetName(),
+				}
+			}
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return false, err
+		}
+		log.Info("Creating recipe object", "kind", obj.GetObjectKind().GroupVersionKind().Kind, "name", objMeta.GetName())
+		err = r.client.Create(context.TODO(), obj)
+		if err != nil {
+			return false, err
+		}
+		ok = false
+	}
+	return ok, nil
+}
+
 func (r *ReconcileComponent) reconcileConfigMap(instance *workspacev1alpha1.Component, cm *corev1.ConfigMap, log logr.Logger) (ok bool, err error) {
 	err = controllerutil.SetControllerReference(instance, cm, r.scheme)
 	if err != nil {
@@ -163,10 +250,22 @@ func (r *ReconcileComponent) reconcileConfigMap(instance *workspacev1alpha1.Comp
 }
 
 func (r *ReconcileComponent) reconcileStatus(instance *workspacev1alpha1.Component, components []workspacev1alpha1.ComponentDescription) error {
-	if instance.Status.Ready && cmp.Equal(instance.Status.ComponentDescriptions, components) {
+	if instance.Status.Ready && instance.Status.Message == "" && cmp.Equal(instance.Status.ComponentDescriptions, components) {
 		return nil
 	}
 	instance.Status.ComponentDescriptions = components
 	instance.Status.Ready = true
+	instance.Status.Message = ""
+	return r.client.Status().Update(context.TODO(), instance)
+}
+
+// reconcileFailure records in the component's status why its components could not be provisioned. Such failures
+// require changes to the devfile (or namespace), so they are not retried until the component changes.
+func (r *ReconcileComponent) reconcileFailure(instance *workspacev1alpha1.Component, message string) error {
+	if !instance.Status.Ready && instance.Status.Message == message {
+		return nil
+	}
+	instance.Status.Ready = false
+	instance.Status.Message = message
 	return r.client.Status().Update(context.TODO(), instance)
 }
\ No newline at end of file
@@ -0,0 +1,41 @@
+package prerequisites
+
+import (
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// generatePrerequisites returns the objects shared by workspaces in namespace. The common PVC is only included if
+// commonStorage is true, i.e. if workspaces use the common storage strategy.
+func generatePrerequisites(namespace string, commonStorage bool) ([]runtime.Object, error) {
+	pvcStorageQuantity, err := resource.ParseQuantity(config.ControllerCfg.GetPVCStorageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	var k8sObjects []runtime.Object
+	if commonStorage {
+		k8sObjects = append(k8sObjects, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.ControllerCfg.GetWorkspacePVCName(),
+				Namespace: namespace,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						"storage": pvcStorageQuantity,
+					},
+				},
+				StorageClassName: config.ControllerCfg.GetPVCStorageClassName(),
+			},
+		})
+	}
+
+	return k8sObjects, nil
+}
@@ -0,0 +1,146 @@
+package prerequisites
+
+import (
+	"context"
+
+	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/provision"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_prerequisites")
+
+// prerequisitesRequestName is the name used in reconcile requests; requests are per namespace, so that
+// prerequisites are reconciled once for all workspaces in a namespace
+const prerequisitesRequestName = "workspace-prerequisites"
+
+// Add creates a new Prerequisites Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePrerequisites{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor("prerequisites-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("prerequisites-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Prerequisites are checked when a workspace is created in a namespace. As the informer lists all existing
+	// workspaces on startup, this also checks every namespace with workspaces once when the operator starts.
+	err = c.Watch(&source.Kind{Type: &workspacev1alpha1.Workspace{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(toNamespaceRequest),
+	}, predicate.Funcs{
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	})
+	if err != nil {
+		return err
+	}
+
+	// The common PVC is recreated if it is deleted while workspaces still use it
+	err = c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(toNamespaceRequest),
+	}, predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+		DeleteFunc: func(evt event.DeleteEvent) bool {
+			return evt.Meta.GetName() == config.ControllerCfg.GetWorkspacePVCName()
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// toNamespaceRequest maps an object to the prerequisites request for its namespace
+func toNamespaceRequest(obj handler.MapObject) []reconcile.Request {
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      prerequisitesRequestName,
+				Namespace: obj.Meta.GetNamespace(),
+			},
+		},
+	}
+}
+
+// blank assignment to verify that ReconcilePrerequisites implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcilePrerequisites{}
+
+// ReconcilePrerequisites reconciles the objects shared by all workspaces in a namespace
+type ReconcilePrerequisites struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Reconcile labels the request's namespace as containing workspaces, creates missing prerequisites in it and updates
+// prerequisites whose managed fields differ from the expected ones. The common PVC is only a prerequisite if any
+// workspace in the namespace uses the common storage strategy. Prerequisites annotated as unmanaged are not
+// updated. Changes are reported as Events on the prerequisite objects.
+func (r *ReconcilePrerequisites) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	namespace := request.Namespace
+	reqLogger := log.WithValues("Request.Namespace", namespace)
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	err := r.client.List(context.TODO(), workspaces, client.InNamespace(namespace))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(workspaces.Items) == 0 {
+		reqLogger.V(1).Info("No workspaces in namespace; skipping prerequisites")
+		return reconcile.Result{}, nil
+	}
+
+	reqLogger.Info("Reconciling workspace prerequisites")
+	err = r.syncNamespaceLabel(namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	commonStorage := false
+	for _, workspace := range workspaces.Items {
+		if provision.GetStorageStrategy(&workspace) == config.CommonStorageStrategy {
+			commonStorage = true
+			break
+		}
+	}
+	prereqs, err := generatePrerequisites(namespace, commonStorage)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	for _, prereq := range prereqs {
+		requeue, err := r.syncPrerequisite(prereq, reqLogger)
+		if err != nil || requeue {
+			return reconcile.Result{Requeue: requeue}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
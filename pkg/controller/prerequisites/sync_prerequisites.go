@@ -0,0 +1,111 @@
+package prerequisites
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventReasonCreated   = "Created"
+	eventReasonUpdated   = "Updated"
+	eventReasonUnmanaged = "Unmanaged"
+	eventReasonFailed    = "SyncFailed"
+)
+
+// syncPrerequisite creates prereq if it does not exist, or updates it if its managed fields differ from the cluster
+// object. Returns true if the request should be requeued, e.g. because the object was modified concurrently.
+func (r *ReconcilePrerequisites) syncPrerequisite(prereq runtime.Object, reqLogger logr.Logger) (requeue bool, err error) {
+	prereqMeta, ok := prereq.(metav1.Object)
+	if !ok {
+		return false, fmt.Errorf("prerequisite %T is not a valid Kubernetes object", prereq)
+	}
+	kind := reflect.TypeOf(prereq).Elem().Name()
+	logger := reqLogger.WithValues("kind", kind, "name", prereqMeta.GetName())
+
+	clusterObj := reflect.New(reflect.TypeOf(prereq).Elem()).Interface().(runtime.Object)
+	namespacedName := types.NamespacedName{Name: prereqMeta.GetName(), Namespace: prereqMeta.GetNamespace()}
+	err = r.client.Get(context.TODO(), namespacedName, clusterObj)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return false, err
+		}
+		logger.Info("Creating prerequisite")
+		err := r.client.Create(context.TODO(), prereq)
+		if err != nil {
+			if errors.IsAlreadyExists(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		r.recorder.Eventf(prereq, corev1.EventTypeNormal, eventReasonCreated, "Created %s %s for workspaces", kind, prereqMeta.GetName())
+		return false, nil
+	}
+
+	if !updateManagedFields(prereq, clusterObj) {
+		return false, nil
+	}
+	if clusterObj.(metav1.Object).GetAnnotations()[config.PrerequisiteUnmanagedAnnotation] == "true" {
+		logger.V(1).Info("Prerequisite differs from expected but is unmanaged; not updating")
+		r.recorder.Eventf(clusterObj, corev1.EventTypeNormal, eventReasonUnmanaged,
+			"%s %s differs from the expected prerequisite but is not updated as it has annotation %s",
+			kind, prereqMeta.GetName(), config.PrerequisiteUnmanagedAnnotation)
+		return false, nil
+	}
+
+	logger.Info("Updating prerequisite")
+	err = r.client.Update(context.TODO(), clusterObj)
+	if err != nil {
+		if errors.IsConflict(err) {
+			return true, nil
+		}
+		r.recorder.Eventf(clusterObj, corev1.EventTypeWarning, eventReasonFailed, "Failed to update %s %s: %s", kind, prereqMeta.GetName(), err)
+		return false, err
+	}
+	r.recorder.Eventf(clusterObj, corev1.EventTypeNormal, eventReasonUpdated, "Updated %s %s to match expected prerequisite", kind, prereqMeta.GetName())
+	return false, nil
+}
+
+// updateManagedFields copies the fields of spec that are managed by the controller to cluster, leaving other fields,
+// e.g. those set by the cluster, untouched. Returns true if cluster was changed.
+//
+// The spec of a PersistentVolumeClaim is immutable once created, except for its storage request, which can be
+// increased if its storage class allows volume expansion. Storage requests are therefore only ever increased.
+func updateManagedFields(spec, cluster runtime.Object) bool {
+	switch specObj := spec.(type) {
+	case *corev1.PersistentVolumeClaim:
+		clusterObj := cluster.(*corev1.PersistentVolumeClaim)
+		specStorage := specObj.Spec.Resources.Requests[corev1.ResourceStorage]
+		clusterStorage := clusterObj.Spec.Resources.Requests[corev1.ResourceStorage]
+		if specStorage.Cmp(clusterStorage) <= 0 {
+			return false
+		}
+		if clusterObj.Spec.Resources.Requests == nil {
+			clusterObj.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		clusterObj.Spec.Resources.Requests[corev1.ResourceStorage] = specStorage
+		return true
+	default:
+		return false
+	}
+}
+
+// syncNamespaceLabel labels namespace as containing workspaces, which enables the pod exec webhook for it. A patch is
+// used as the operator does not read namespaces.
+func (r *ReconcilePrerequisites) syncNamespaceLabel(namespace string) error {
+	patch := fmt.Sprintf(`{"metadata":{"labels":{%q:"true"}}}`, config.WorkspaceNamespaceLabel)
+	return r.client.Patch(context.TODO(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+		},
+	}, client.ConstantPatch(types.MergePatchType, []byte(patch)))
+}
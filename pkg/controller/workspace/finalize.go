@@ -0,0 +1,81 @@
+package workspace
+
+import (
+	"context"
+
+	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/provision"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// finalizeWorkspace removes the workspace's files from the common PVC before allowing the workspace to be deleted.
+// The workspace deployment is scaled down first, so that nothing writes to the PVC during cleanup. The finalizer is
+// only removed once the cleanup job succeeds; failures are reported in the workspace status and the job is retried.
+// Cleanup can be skipped by setting the skip-storage-cleanup annotation on the workspace.
+func (r *ReconcileWorkspace) finalizeWorkspace(
+	workspace *workspacev1alpha1.Workspace,
+	status *currentStatus,
+	clusterAPI provision.ClusterAPI) (reconcile.Result, error) {
+
+	if !hasFinalizer(workspace, config.StorageCleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
+	status.Phase = workspacev1alpha1.WorkspaceStatusTerminating
+
+	if workspace.Annotations[config.WorkspaceSkipStorageCleanupAnnotation] == "true" {
+		clusterAPI.Logger.Info("Skipping workspace storage cleanup; removing finalizer")
+		removeFinalizer(workspace, config.StorageCleanupFinalizer)
+		return reconcile.Result{}, r.client.Update(context.TODO(), workspace)
+	}
+
+	deploymentStatus := provision.ScaleDeploymentToZero(workspace, clusterAPI)
+	if !deploymentStatus.Continue {
+		clusterAPI.Logger.Info("Waiting on workspace deployment to scale down")
+		return reconcile.Result{Requeue: deploymentStatus.Requeue}, deploymentStatus.Err
+	}
+
+	cleanupStatus := provision.CleanupCommonPVC(workspace, clusterAPI)
+	if !status.checkStage(workspacev1alpha1.WorkspaceConditionStorageCleanedUp, cleanupStatus.ProvisioningStatus, "Waiting for workspace storage cleanup job") {
+		if cleanupStatus.RequeueAfter > 0 {
+			clusterAPI.Logger.Info("Workspace storage cleanup job failed; retrying", "after", cleanupStatus.RequeueAfter)
+			return reconcile.Result{RequeueAfter: cleanupStatus.RequeueAfter}, nil
+		}
+		clusterAPI.Logger.Info("Waiting for workspace storage cleanup job")
+		return reconcile.Result{Requeue: cleanupStatus.Requeue}, cleanupStatus.Err
+	}
+
+	clusterAPI.Logger.Info("Workspace storage cleaned up; removing finalizer")
+	removeFinalizer(workspace, config.StorageCleanupFinalizer)
+	return reconcile.Result{}, r.client.Update(context.TODO(), workspace)
+}
+
+// ensureFinalizer adds the storage cleanup finalizer to workspaces that store files on the common PVC. Returns true if
+// the workspace was updated.
+func (r *ReconcileWorkspace) ensureFinalizer(workspace *workspacev1alpha1.Workspace) (updated bool, err error) {
+	if provision.GetStorageStrategy(workspace) != config.CommonStorageStrategy ||
+		hasFinalizer(workspace, config.StorageCleanupFinalizer) {
+		return false, nil
+	}
+	workspace.SetFinalizers(append(workspace.GetFinalizers(), config.StorageCleanupFinalizer))
+	return true, r.client.Update(context.TODO(), workspace)
+}
+
+func hasFinalizer(workspace *workspacev1alpha1.Workspace, finalizer string) bool {
+	for _, f := range workspace.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(workspace *workspacev1alpha1.Workspace, finalizer string) {
+	var finalizers []string
+	for _, f := range workspace.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	workspace.SetFinalizers(finalizers)
+}
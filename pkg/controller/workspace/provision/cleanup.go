@@ -0,0 +1,200 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strconv"
+	"time"
+)
+
+const (
+	cleanupJobBackoffLimit = 3
+
+	// cleanupJobAttemptAnnotation records how many times the cleanup Job has been recreated after failing
+	cleanupJobAttemptAnnotation = "org.eclipse.che.workspace/cleanup-attempt"
+
+	cleanupJobRetryBaseDelay = 30 * time.Second
+	cleanupJobRetryMaxDelay  = 10 * time.Minute
+)
+
+type CleanupProvisioningStatus struct {
+	ProvisioningStatus
+	// RequeueAfter is set when a failed cleanup Job will be retried after a delay
+	RequeueAfter time.Duration
+}
+
+// CleanupCommonPVC runs a Job that removes the workspace's files from the common PVC. Continue is true once the
+// Job has completed successfully, or if the common PVC does not exist. If the Job fails, the returned error contains
+// the reason and the Job is recreated after an increasing delay.
+func CleanupCommonPVC(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) CleanupProvisioningStatus {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := clusterAPI.Client.Get(context.TODO(), types.NamespacedName{
+		Name:      config.ControllerCfg.GetWorkspacePVCName(),
+		Namespace: workspace.Namespace,
+	}, pvc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Nothing to clean up; the Job would stay pending forever
+			clusterAPI.Logger.Info("Common PVC does not exist; skipping workspace storage cleanup")
+			return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Continue: true}}
+		}
+		return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+
+	specJob, err := getSpecCommonPVCCleanupJob(workspace, clusterAPI)
+	if err != nil {
+		return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+
+	clusterJob := &batchv1.Job{}
+	namespacedName := types.NamespacedName{
+		Name:      specJob.Name,
+		Namespace: specJob.Namespace,
+	}
+	err = clusterAPI.Client.Get(context.TODO(), namespacedName, clusterJob)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			clusterAPI.Logger.Info("Creating workspace storage cleanup job")
+			err := clusterAPI.Client.Create(context.TODO(), specJob)
+			return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Requeue: true, Err: err}}
+		}
+		return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+
+	for _, condition := range clusterJob.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Continue: true}}
+		case batchv1.JobFailed:
+			return retryFailedCleanupJob(clusterJob, specJob, condition, clusterAPI)
+		}
+	}
+	return CleanupProvisioningStatus{}
+}
+
+// retryFailedCleanupJob replaces the failed cleanup Job with specJob once the retry delay since its failure has passed.
+// The delay doubles with each attempt, up to cleanupJobRetryMaxDelay.
+func retryFailedCleanupJob(clusterJob, specJob *batchv1.Job, failed batchv1.JobCondition, clusterAPI ClusterAPI) CleanupProvisioningStatus {
+	attempt, _ := strconv.Atoi(clusterJob.Annotations[cleanupJobAttemptAnnotation])
+	delay := cleanupJobRetryMaxDelay
+	if attempt < 5 {
+		delay = cleanupJobRetryBaseDelay << uint(attempt)
+	}
+	if delay > cleanupJobRetryMaxDelay {
+		delay = cleanupJobRetryMaxDelay
+	}
+	if remaining := delay - time.Since(failed.LastTransitionTime.Time); remaining > 0 {
+		return CleanupProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
+				Err: fmt.Errorf("workspace storage cleanup job %s failed: %s", clusterJob.Name, failed.Message),
+			},
+			RequeueAfter: remaining,
+		}
+	}
+
+	clusterAPI.Logger.Info("Recreating failed workspace storage cleanup job", "attempt", attempt+1)
+	// The UID precondition avoids deleting a Job that was already recreated, if the cached Job is out of date
+	propagationPolicy := metav1.DeletePropagationBackground
+	err := clusterAPI.Client.Delete(context.TODO(), clusterJob, &client.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+		Preconditions:     &metav1.Preconditions{UID: &clusterJob.UID},
+	})
+	if err != nil {
+		if errors.IsNotFound(err) || errors.IsConflict(err) {
+			return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Requeue: true}}
+		}
+		return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+	specJob.Annotations = map[string]string{
+		cleanupJobAttemptAnnotation: strconv.Itoa(attempt + 1),
+	}
+	err = clusterAPI.Client.Create(context.TODO(), specJob)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+	return CleanupProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Requeue: true}}
+}
+
+func getSpecCommonPVCCleanupJob(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) (*batchv1.Job, error) {
+	workspaceId := workspace.Status.WorkspaceId
+	backoffLimit := int32(cleanupJobBackoffLimit)
+
+	var user *int64
+	if !config.ControllerCfg.IsOpenShift() {
+		uID := int64(1234)
+		user = &uID
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cleanup-" + workspaceId,
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				config.WorkspaceIDLabel: workspaceId,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						config.WorkspaceIDLabel: workspaceId,
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsUser: user,
+						FSGroup:   user,
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: config.ControllerCfg.GetWorkspacePVCName(),
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: config.ControllerCfg.GetWorkspacePVCName(),
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "cleanup",
+							Image:   "registry.access.redhat.com/ubi8/ubi-minimal",
+							Command: []string{"/bin/sh", "-c"},
+							Args: []string{
+								"rm -rf /tmp/che-workspaces/" + workspaceId,
+							},
+							ImagePullPolicy: corev1.PullPolicy(config.ControllerCfg.GetSidecarPullPolicy()),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									MountPath: "/tmp/che-workspaces",
+									Name:      config.ControllerCfg.GetWorkspacePVCName(),
+								},
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := controllerutil.SetControllerReference(workspace, job, clusterAPI.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -28,14 +28,14 @@ var componentDiffOpts = cmp.Options{
 
 func SyncComponentsToCluster(
 		workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) ComponentProvisioningStatus {
-	specComponents, err := getSpecComponents(workspace, clusterAPI.Scheme)
+	clusterComponents, err := getClusterComponents(workspace, clusterAPI.Client)
 	if err != nil {
 		return ComponentProvisioningStatus{
 			ProvisioningStatus: ProvisioningStatus{Err: err},
 		}
 	}
 
-	clusterComponents, err := getClusterComponents(workspace, clusterAPI.Client)
+	specComponents, err := getSpecComponents(workspace, clusterComponents, clusterAPI.Scheme)
 	if err != nil {
 		return ComponentProvisioningStatus{
 			ProvisioningStatus: ProvisioningStatus{Err: err},
@@ -88,6 +88,13 @@ func SyncComponentsToCluster(
 func checkComponentsReadiness(components []v1alpha1.Component) ComponentProvisioningStatus {
 	var componentDescriptions []v1alpha1.ComponentDescription
 	for _, component := range components {
+		if component.Status.Message != "" {
+			return ComponentProvisioningStatus{
+				ProvisioningStatus: ProvisioningStatus{
+					Err: fmt.Errorf("failed to provision component %s: %s", component.Name, component.Status.Message),
+				},
+			}
+		}
 		if !component.Status.Ready {
 			return ComponentProvisioningStatus{
 				ProvisioningStatus: ProvisioningStatus{},
@@ -103,8 +110,22 @@ func checkComponentsReadiness(components []v1alpha1.Component) ComponentProvisio
 	}
 }
 
-func getSpecComponents(workspace *v1alpha1.Workspace, scheme *runtime.Scheme) ([]v1alpha1.Component, error) {
-	dockerComponents, pluginComponents, err := adaptor.SortComponentsByType(workspace.Spec.Devfile.Components)
+func getSpecComponents(workspace *v1alpha1.Workspace, clusterComponents []v1alpha1.Component, scheme *runtime.Scheme) ([]v1alpha1.Component, error) {
+	dockerComponents, pluginComponents, kubernetesComponents, err := adaptor.SortComponentsByType(workspace.Spec.Devfile.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recipes referenced by kubernetes and openshift components are stored in the kubernetes Component rather than in
+	// the workspace, as the workspace's spec is owned by the user
+	kubernetesResolverName := fmt.Sprintf("components-%s-%s", workspace.Status.WorkspaceId, "kubernetes")
+	var resolvedKubernetesComponents []v1alpha1.ComponentSpec
+	for _, clusterComponent := range clusterComponents {
+		if clusterComponent.Name == kubernetesResolverName {
+			resolvedKubernetesComponents = clusterComponent.Spec.Components
+		}
+	}
+	err = adaptor.ResolveRecipeReferences(kubernetesComponents, resolvedKubernetesComponents)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +158,20 @@ func getSpecComponents(workspace *v1alpha1.Workspace, scheme *runtime.Scheme) ([
 			Commands: workspace.Spec.Devfile.Commands,
 		},
 	}
+	kubernetesResolver := v1alpha1.Component{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      kubernetesResolverName,
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				"app": workspace.Status.WorkspaceId,
+			},
+		},
+		Spec: v1alpha1.WorkspaceComponentSpec{
+			WorkspaceId: workspace.Status.WorkspaceId,
+			Components:  kubernetesComponents,
+			Commands: workspace.Spec.Devfile.Commands,
+		},
+	}
 	err = controllerutil.SetControllerReference(workspace, &dockerResolver, scheme)
 	if err != nil {
 		return nil, err
@@ -145,8 +180,12 @@ func getSpecComponents(workspace *v1alpha1.Workspace, scheme *runtime.Scheme) ([
 	if err != nil {
 		return nil, err
 	}
+	err = controllerutil.SetControllerReference(workspace, &kubernetesResolver, scheme)
+	if err != nil {
+		return nil, err
+	}
 
-	return []v1alpha1.Component{pluginResolver, dockerResolver}, nil
+	return []v1alpha1.Component{pluginResolver, dockerResolver, kubernetesResolver}, nil
 }
 
 func getClusterComponents(workspace *v1alpha1.Workspace, client runtimeClient.Client) ([]v1alpha1.Component, error) {
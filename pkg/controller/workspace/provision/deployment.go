@@ -93,6 +93,32 @@ func SyncDeploymentToCluster(
 	return DeploymentProvisioningStatus{}
 }
 
+// ScaleDeploymentToZero scales the workspace deployment (if it exists) down to zero replicas. Continue is true once no
+// workspace pods remain; the deployment itself is kept so that the workspace can be started again.
+func ScaleDeploymentToZero(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) ProvisioningStatus {
+	clusterDeployment, err := getClusterDeployment(workspace.Status.WorkspaceId, workspace.Namespace, clusterAPI.Client)
+	if err != nil {
+		return ProvisioningStatus{Err: err}
+	}
+	if clusterDeployment == nil {
+		return ProvisioningStatus{Continue: true}
+	}
+
+	if clusterDeployment.Spec.Replicas == nil || *clusterDeployment.Spec.Replicas != 0 {
+		clusterAPI.Logger.Info("Scaling workspace deployment to zero")
+		replicas := int32(0)
+		clusterDeployment.Spec.Replicas = &replicas
+		err := clusterAPI.Client.Update(context.TODO(), clusterDeployment)
+		return ProvisioningStatus{Requeue: true, Err: err}
+	}
+
+	if clusterDeployment.Status.Replicas != 0 {
+		return ProvisioningStatus{}
+	}
+
+	return ProvisioningStatus{Continue: true}
+}
+
 func checkDeploymentStatus(deployment *appsv1.Deployment) (ready bool) {
 	// TODO: available doesn't mean what you might think
 	for _, condition := range deployment.Status.Conditions {
@@ -123,12 +149,21 @@ func getSpecDeployment(
 		return nil, err
 	}
 
+	initContainers := append(podAdditions.InitContainers, precreateSubpathsInitContainer(workspace.Status.WorkspaceId))
+	projectClonerContainer, err := getProjectClonerInitContainer(workspace)
+	if err != nil {
+		return nil, err
+	}
+	if projectClonerContainer != nil {
+		initContainers = append(initContainers, *projectClonerContainer)
+	}
+
 	commonEnv := env.CommonEnvironmentVariables(workspace.Name, workspace.Status.WorkspaceId, workspace.Namespace)
 	for idx := range podAdditions.Containers {
 		podAdditions.Containers[idx].Env = append(podAdditions.Containers[idx].Env, commonEnv...)
 	}
-	for idx := range podAdditions.InitContainers {
-		podAdditions.InitContainers[idx].Env = append(podAdditions.InitContainers[idx].Env, commonEnv...)
+	for idx := range initContainers {
+		initContainers[idx].Env = append(initContainers[idx].Env, commonEnv...)
 	}
 
 	deployment := &appsv1.Deployment{
@@ -168,9 +203,9 @@ func getSpecDeployment(
 					},
 				},
 				Spec: corev1.PodSpec{
-					InitContainers:                append(podAdditions.InitContainers, precreateSubpathsInitContainer(workspace.Status.WorkspaceId)),
+					InitContainers:                initContainers,
 					Containers:                    podAdditions.Containers,
-					Volumes:                       append(podAdditions.Volumes, getPersistentVolumeClaim()),
+					Volumes:                       append(podAdditions.Volumes, getWorkspaceVolume(workspace)),
 					ImagePullSecrets:              podAdditions.PullSecrets,
 					RestartPolicy:                 "Always",
 					TerminationGracePeriodSeconds: &terminationGracePeriod,
@@ -185,6 +220,16 @@ func getSpecDeployment(
 		},
 	}
 
+	// Labels and annotations contributed by components are added without overriding those set by the controller
+	for labelKey, labelVal := range podAdditions.Labels {
+		if _, exists := deployment.Spec.Template.Labels[labelKey]; !exists {
+			deployment.Spec.Template.Labels[labelKey] = labelVal
+		}
+	}
+	if len(podAdditions.Annotations) > 0 {
+		deployment.Spec.Template.Annotations = podAdditions.Annotations
+	}
+
 	err = controllerutil.SetControllerReference(workspace, deployment, scheme)
 	if err != nil {
 		return nil, err
@@ -210,7 +255,10 @@ func getClusterDeployment(name string, namespace string, client runtimeClient.Cl
 }
 
 func mergePodAdditions(toMerge []v1alpha1.PodAdditions) (*v1alpha1.PodAdditions, error) {
-	podAdditions := &v1alpha1.PodAdditions{}
+	podAdditions := &v1alpha1.PodAdditions{
+		Annotations: map[string]string{},
+		Labels:      map[string]string{},
+	}
 
 	// "Set"s to store k8s object names and detect duplicates
 	containerNames := map[string]bool{}
@@ -259,19 +307,6 @@ func mergePodAdditions(toMerge []v1alpha1.PodAdditions) (*v1alpha1.PodAdditions,
 	return podAdditions, nil
 }
 
-func getPersistentVolumeClaim() corev1.Volume {
-	var workspaceClaim = corev1.PersistentVolumeClaimVolumeSource{
-		ClaimName: config.ControllerCfg.GetWorkspacePVCName(),
-	}
-	pvcVolume := corev1.Volume{
-		Name: config.ControllerCfg.GetWorkspacePVCName(),
-		VolumeSource: corev1.VolumeSource{
-			PersistentVolumeClaim: &workspaceClaim,
-		},
-	}
-	return pvcVolume
-}
-
 func precreateSubpathsInitContainer(workspaceId string) corev1.Container {
 	initContainer := corev1.Container{
 		Name:    "precreate-subpaths",
@@ -283,6 +318,7 @@ func precreateSubpathsInitContainer(workspaceId string) corev1.Container {
 			"-m",
 			"777",
 			"/tmp/che-workspaces/" + workspaceId,
+			"/tmp/che-workspaces/" + workspaceId + config.DefaultProjectsSourcesRoot,
 		},
 		ImagePullPolicy: corev1.PullPolicy(config.ControllerCfg.GetSidecarPullPolicy()),
 		VolumeMounts: []corev1.VolumeMount{
@@ -0,0 +1,117 @@
+package provision
+
+import (
+	"fmt"
+	"github.com/che-incubator/che-workspace-operator/pkg/adaptor"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"path"
+	"strings"
+)
+
+const projectClonerContainerName = "project-cloner"
+
+// getProjectClonerInitContainer returns an init container that imports the devfile's projects into the workspace's
+// projects directory. Projects that already exist on the PVC are skipped, so that changes made in the workspace are
+// preserved across restarts. Returns nil if the devfile does not define any projects.
+func getProjectClonerInitContainer(workspace *v1alpha1.Workspace) (*corev1.Container, error) {
+	projects := workspace.Spec.Devfile.Projects
+	if len(projects) == 0 {
+		return nil, nil
+	}
+
+	script, err := getProjectClonerScript(projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Container{
+		Name:    projectClonerContainerName,
+		Image:   config.ControllerCfg.GetProjectClonerImage(),
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{script},
+		Env: []corev1.EnvVar{
+			{
+				// Image may run as an arbitrary user without a home directory; git needs a writable $HOME
+				Name:  "HOME",
+				Value: "/tmp",
+			},
+		},
+		ImagePullPolicy: corev1.PullPolicy(config.ControllerCfg.GetSidecarPullPolicy()),
+		VolumeMounts: []corev1.VolumeMount{
+			adaptor.GetProjectSourcesVolumeMount(workspace.Status.WorkspaceId),
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}, nil
+}
+
+// getProjectClonerScript builds a shell script that imports each project in turn. A project that fails to import is
+// logged and removed, so that it is retried on the next workspace start, but does not block the workspace from starting.
+func getProjectClonerScript(projects []v1alpha1.ProjectSpec) (string, error) {
+	var script strings.Builder
+	for _, project := range projects {
+		if project.Name == "" || strings.ContainsAny(project.Name, "/\\") || project.Name == "." || project.Name == ".." {
+			return "", fmt.Errorf("invalid project name '%s'", project.Name)
+		}
+		projectPath := path.Join(config.DefaultProjectsSourcesRoot, project.Name)
+
+		var importCommands []string
+		switch project.Source.Type {
+		case v1alpha1.GitProjectSourceType, v1alpha1.GithubProjectSourceType:
+			importCommands = getGitCloneCommands(project.Source, projectPath)
+		case v1alpha1.ZipProjectSourceType:
+			importCommands = getZipExtractCommands(project.Source, projectPath)
+		default:
+			return "", fmt.Errorf("unsupported source type '%s' for project %s", project.Source.Type, project.Name)
+		}
+
+		fmt.Fprintf(&script, "if [ -e %s ]; then\n", shellQuote(projectPath))
+		fmt.Fprintf(&script, "  echo %s\n", shellQuote(fmt.Sprintf("Project %s already exists; skipping", project.Name)))
+		fmt.Fprintf(&script, "elif ! { %s; }; then\n", strings.Join(importCommands, " && "))
+		fmt.Fprintf(&script, "  echo %s >&2\n", shellQuote(fmt.Sprintf("Failed to import project %s", project.Name)))
+		fmt.Fprintf(&script, "  rm -rf %s\n", shellQuote(projectPath))
+		fmt.Fprintf(&script, "fi\n")
+	}
+	return script.String(), nil
+}
+
+func getGitCloneCommands(source v1alpha1.ProjectSourceSpec, projectPath string) []string {
+	cloneCmd := "git clone"
+	if source.Branch != "" {
+		cloneCmd += " --branch " + shellQuote(source.Branch)
+	} else if source.Tag != "" {
+		cloneCmd += " --branch " + shellQuote(source.Tag)
+	}
+	cloneCmd += " " + shellQuote(source.Location) + " " + shellQuote(projectPath)
+	commands := []string{cloneCmd}
+
+	checkoutRef := source.CommitId
+	if checkoutRef == "" {
+		checkoutRef = source.StartPoint
+	}
+	if checkoutRef != "" {
+		if source.Branch != "" {
+			// Keep the requested branch name, but reset it to the requested commit
+			commands = append(commands, fmt.Sprintf("git -C %s checkout -B %s %s", shellQuote(projectPath), shellQuote(source.Branch), shellQuote(checkoutRef)))
+		} else {
+			commands = append(commands, fmt.Sprintf("git -C %s checkout %s", shellQuote(projectPath), shellQuote(checkoutRef)))
+		}
+	}
+	return commands
+}
+
+func getZipExtractCommands(source v1alpha1.ProjectSourceSpec, projectPath string) []string {
+	archivePath := projectPath + ".zip"
+	return []string{
+		fmt.Sprintf("mkdir -p %s", shellQuote(projectPath)),
+		fmt.Sprintf("wget -q -O %s %s", shellQuote(archivePath), shellQuote(source.Location)),
+		fmt.Sprintf("unzip -q %s -d %s", shellQuote(archivePath), shellQuote(projectPath)),
+		fmt.Sprintf("rm -f %s", shellQuote(archivePath)),
+	}
+}
+
+// shellQuote wraps a string in single quotes so that it is interpreted literally by the shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
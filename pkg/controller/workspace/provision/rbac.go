@@ -0,0 +1,180 @@
+package provision
+
+import (
+	"context"
+	"sort"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// SyncRBACToCluster creates a Role and RoleBinding that grant the workspace's ServiceAccount access to the workspace's
+// own Workspace object and pods, and nothing else. It is called before the workspace's deployment is created, at which
+// point the Role only grants access to the Workspace object; as pod names are generated, rules for the pods are added
+// once they exist and updated whenever they change. Both objects are owned by the workspace, so its grants are removed
+// along with it.
+func SyncRBACToCluster(workspace *v1alpha1.Workspace, serviceAcctName string, clusterAPI ClusterAPI) ProvisioningStatus {
+	podNames, err := getWorkspacePodNames(workspace, clusterAPI.Client)
+	if err != nil {
+		return ProvisioningStatus{Err: err}
+	}
+
+	specRole := getSpecRole(workspace, podNames)
+	specRoleBinding := getSpecRoleBinding(workspace, serviceAcctName)
+	for _, obj := range []metav1.Object{specRole, specRoleBinding} {
+		err := controllerutil.SetControllerReference(workspace, obj, clusterAPI.Scheme)
+		if err != nil {
+			return ProvisioningStatus{Err: err}
+		}
+	}
+
+	clusterRole := &rbacv1.Role{}
+	ok, err := syncRBACObject(specRole, clusterRole, clusterAPI, func() bool {
+		if cmp.Equal(specRole.Rules, clusterRole.Rules) {
+			return false
+		}
+		clusterRole.Rules = specRole.Rules
+		return true
+	})
+	if !ok || err != nil {
+		return ProvisioningStatus{Requeue: true, Err: err}
+	}
+
+	clusterRoleBinding := &rbacv1.RoleBinding{}
+	ok, err = syncRBACObject(specRoleBinding, clusterRoleBinding, clusterAPI, func() bool {
+		if cmp.Equal(specRoleBinding.Subjects, clusterRoleBinding.Subjects) {
+			return false
+		}
+		clusterRoleBinding.Subjects = specRoleBinding.Subjects
+		return true
+	})
+	if !ok || err != nil {
+		return ProvisioningStatus{Requeue: true, Err: err}
+	}
+
+	return ProvisioningStatus{Continue: true}
+}
+
+// syncRBACObject creates spec if it does not exist on the cluster, reading the cluster object into cluster
+// otherwise. If updateCluster reports that it changed cluster, the cluster object is updated. Returns true if the
+// object was already in sync.
+func syncRBACObject(
+	spec runtime.Object,
+	cluster runtime.Object,
+	clusterAPI ClusterAPI,
+	updateCluster func() bool) (ok bool, err error) {
+
+	specMeta := spec.(metav1.Object)
+	namespacedName := types.NamespacedName{
+		Name:      specMeta.GetName(),
+		Namespace: specMeta.GetNamespace(),
+	}
+	err = clusterAPI.Client.Get(context.TODO(), namespacedName, cluster)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			clusterAPI.Logger.Info("Creating workspace RBAC object", "name", specMeta.GetName())
+			return false, clusterAPI.Client.Create(context.TODO(), spec)
+		}
+		return false, err
+	}
+	if updateCluster() {
+		clusterAPI.Logger.Info("Updating workspace RBAC object", "name", specMeta.GetName())
+		return false, clusterAPI.Client.Update(context.TODO(), cluster)
+	}
+	return true, nil
+}
+
+func getSpecRole(workspace *v1alpha1.Workspace, podNames []string) *rbacv1.Role {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{v1alpha1.SchemeGroupVersion.Group},
+			Resources:     []string{"workspaces"},
+			ResourceNames: []string{workspace.Name},
+			Verbs:         []string{"get"},
+		},
+	}
+	// A rule with empty resourceNames would apply to all pods in the namespace
+	if len(podNames) > 0 {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups:     []string{""},
+				Resources:     []string{"pods"},
+				ResourceNames: podNames,
+				Verbs:         []string{"get"},
+			},
+			rbacv1.PolicyRule{
+				APIGroups:     []string{""},
+				Resources:     []string{"pods/exec"},
+				ResourceNames: podNames,
+				Verbs:         []string{"create"},
+			})
+	}
+
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getWorkspaceRBACName(workspace),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				config.WorkspaceIDLabel: workspace.Status.WorkspaceId,
+			},
+		},
+		Rules: rules,
+	}
+}
+
+func getSpecRoleBinding(workspace *v1alpha1.Workspace, serviceAcctName string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getWorkspaceRBACName(workspace),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				config.WorkspaceIDLabel: workspace.Status.WorkspaceId,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     getWorkspaceRBACName(workspace),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAcctName,
+				Namespace: workspace.Namespace,
+			},
+		},
+	}
+}
+
+// getWorkspacePodNames returns the sorted names of the pods of the workspace's deployment
+func getWorkspacePodNames(workspace *v1alpha1.Workspace, client runtimeClient.Client) ([]string, error) {
+	pods := &corev1.PodList{}
+	err := client.List(context.TODO(), pods,
+		runtimeClient.InNamespace(workspace.Namespace),
+		runtimeClient.MatchingLabels{
+			"app":                   workspace.Status.WorkspaceId,
+			config.WorkspaceIDLabel: workspace.Status.WorkspaceId,
+		})
+	if err != nil {
+		return nil, err
+	}
+	var podNames []string
+	for _, pod := range pods.Items {
+		podNames = append(podNames, pod.Name)
+	}
+	sort.Strings(podNames)
+	return podNames, nil
+}
+
+func getWorkspaceRBACName(workspace *v1alpha1.Workspace) string {
+	return "che-" + workspace.Status.WorkspaceId
+}
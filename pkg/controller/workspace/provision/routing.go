@@ -11,6 +11,8 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strings"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -19,6 +21,8 @@ type RoutingProvisioningStatus struct {
 	ProvisioningStatus
 	PodAdditions     *v1alpha1.PodAdditions
 	ExposedEndpoints map[string][]v1alpha1.ExposedEndpoint
+	// Message explains why the routing is not ready, if known
+	Message string
 }
 
 var routingDiffOpts = cmp.Options{
@@ -51,8 +55,14 @@ func SyncRoutingToCluster(
 		}
 	}
 
-	if !cmp.Equal(specRouting, clusterRouting, routingDiffOpts) {
+	specRotation := specRouting.Annotations[config2.WorkspaceCookieSecretRotationAnnotation]
+	clusterRotation := clusterRouting.Annotations[config2.WorkspaceCookieSecretRotationAnnotation]
+	if !cmp.Equal(specRouting, clusterRouting, routingDiffOpts) || specRotation != clusterRotation {
 		clusterRouting.Spec = specRouting.Spec
+		if clusterRouting.Annotations == nil {
+			clusterRouting.Annotations = map[string]string{}
+		}
+		clusterRouting.Annotations[config2.WorkspaceCookieSecretRotationAnnotation] = specRotation
 		err := clusterAPI.Client.Update(context.TODO(), clusterRouting)
 		return RoutingProvisioningStatus{
 			ProvisioningStatus: ProvisioningStatus{Requeue: true, Err: err},
@@ -65,6 +75,7 @@ func SyncRoutingToCluster(
 				Continue:              false,
 				Requeue:               false,
 			},
+			Message: getUnreadyEndpointsMessage(clusterRouting.Status.EndpointStatuses),
 		}
 	}
 
@@ -77,6 +88,25 @@ func SyncRoutingToCluster(
 	}
 }
 
+// DeleteRouting removes the workspace's WorkspaceRouting, if present. Services, ingresses and routes created for the
+// routing are owned by it and are garbage-collected along with it.
+func DeleteRouting(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) ProvisioningStatus {
+	clusterRouting, err := getClusterRouting(getRoutingName(workspace.Status.WorkspaceId), workspace.Namespace, clusterAPI.Client)
+	if err != nil {
+		return ProvisioningStatus{Err: err}
+	}
+	if clusterRouting == nil {
+		return ProvisioningStatus{Continue: true}
+	}
+
+	clusterAPI.Logger.Info("Deleting workspace routing")
+	err = clusterAPI.Client.Delete(context.TODO(), clusterRouting)
+	if err != nil && !errors.IsNotFound(err) {
+		return ProvisioningStatus{Err: err}
+	}
+	return ProvisioningStatus{Requeue: true}
+}
+
 func getSpecRouting(
 		workspace *v1alpha1.Workspace,
 		componentDescriptions []v1alpha1.ComponentDescription,
@@ -89,7 +119,7 @@ func getSpecRouting(
 
 	routing := &v1alpha1.WorkspaceRouting{
 		ObjectMeta: v1.ObjectMeta{
-			Name:      fmt.Sprintf("routing-%s", workspace.Status.WorkspaceId),
+			Name:      getRoutingName(workspace.Status.WorkspaceId),
 			Namespace: workspace.Namespace,
 		},
 		Spec: v1alpha1.WorkspaceRoutingSpec{
@@ -100,8 +130,16 @@ func getSpecRouting(
 			PodSelector: map[string]string{
 				"app": workspace.Status.WorkspaceId,
 			},
+			Creator: workspace.Annotations[config2.WorkspaceCreatorAnnotation],
+			Access:  workspace.Spec.Access,
 		},
 	}
+	if rotation, ok := workspace.Annotations[config2.WorkspaceCookieSecretRotationAnnotation]; ok {
+		routing.Annotations = map[string]string{
+			config2.WorkspaceCookieSecretRotationAnnotation: rotation,
+		}
+	}
+
 	err := controllerutil.SetControllerReference(workspace, routing, scheme)
 	if err != nil {
 		return nil, err
@@ -125,3 +163,19 @@ func getClusterRouting(name string, namespace string, client runtimeClient.Clien
 	}
 	return routing, nil
 }
+
+func getRoutingName(workspaceId string) string {
+	return fmt.Sprintf("routing-%s", workspaceId)
+}
+
+// getUnreadyEndpointsMessage lists the endpoints that are not ready and why, sorted by endpoint name
+func getUnreadyEndpointsMessage(endpointStatuses map[string]v1alpha1.EndpointStatus) string {
+	var messages []string
+	for name, status := range endpointStatuses {
+		if !status.Ready {
+			messages = append(messages, fmt.Sprintf("endpoint %s: %s", name, status.Message))
+		}
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
@@ -19,4 +19,4 @@ func SyncWorkspaceStatus(workspace *v1alpha1.Workspace, runtime string, clusterA
 		Requeue:  true,
 		Err:      err,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,149 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// GetStorageStrategy returns the storage strategy used for a workspace. Workspaces that explicitly disable
+// persistVolumes in their devfile always use ephemeral storage. Other workspaces use the strategy recorded by
+// SyncStorageStrategy, or the configured strategy if none is recorded yet.
+func GetStorageStrategy(workspace *v1alpha1.Workspace) string {
+	persistVolumes := workspace.Spec.Devfile.DevfileAttributes.PersistVolumes
+	if persistVolumes != nil && !*persistVolumes {
+		return config.EphemeralStorageStrategy
+	}
+	if strategy, ok := workspace.Annotations[config.WorkspaceStorageStrategyAnnotation]; ok {
+		return strategy
+	}
+	return config.ControllerCfg.GetStorageStrategy()
+}
+
+// SyncStorageStrategy records the configured storage strategy on the workspace when it is first reconciled. The
+// workspace keeps using the recorded strategy if the configured strategy is changed later.
+func SyncStorageStrategy(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) ProvisioningStatus {
+	if _, ok := workspace.Annotations[config.WorkspaceStorageStrategyAnnotation]; ok {
+		return ProvisioningStatus{Continue: true}
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	strategy := config.ControllerCfg.GetStorageStrategy()
+	workspace.Annotations[config.WorkspaceStorageStrategyAnnotation] = strategy
+	clusterAPI.Logger.Info("Recording workspace storage strategy", "strategy", strategy)
+	err := clusterAPI.Client.Update(context.TODO(), workspace)
+	return ProvisioningStatus{Requeue: true, Err: err}
+}
+
+// SyncStorageToCluster creates the workspace's PVC if the workspace uses the per-workspace storage strategy. The PVC
+// is owned by the workspace and is not updated once created.
+func SyncStorageToCluster(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) ProvisioningStatus {
+	switch strategy := GetStorageStrategy(workspace); strategy {
+	case config.CommonStorageStrategy, config.EphemeralStorageStrategy:
+		return ProvisioningStatus{Continue: true}
+	case config.PerWorkspaceStorageStrategy:
+		break
+	default:
+		return ProvisioningStatus{
+			Err: fmt.Errorf("unsupported storage strategy '%s'", strategy),
+		}
+	}
+
+	specPVC, err := getSpecPerWorkspacePVC(workspace, clusterAPI)
+	if err != nil {
+		return ProvisioningStatus{Err: err}
+	}
+
+	clusterPVC := &corev1.PersistentVolumeClaim{}
+	namespacedName := types.NamespacedName{
+		Name:      specPVC.Name,
+		Namespace: specPVC.Namespace,
+	}
+	err = clusterAPI.Client.Get(context.TODO(), namespacedName, clusterPVC)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			clusterAPI.Logger.Info("Creating workspace PVC")
+			err := clusterAPI.Client.Create(context.TODO(), specPVC)
+			return ProvisioningStatus{Requeue: true, Err: err}
+		}
+		return ProvisioningStatus{Err: err}
+	}
+
+	return ProvisioningStatus{Continue: true}
+}
+
+func getSpecPerWorkspacePVC(workspace *v1alpha1.Workspace, clusterAPI ClusterAPI) (*corev1.PersistentVolumeClaim, error) {
+	storageSize := workspace.Spec.Devfile.DevfileAttributes.StorageSize
+	if storageSize == "" {
+		storageSize = config.ControllerCfg.GetPVCStorageSize()
+	}
+	pvcStorageQuantity, err := resource.ParseQuantity(storageSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage size '%s': %w", storageSize, err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getPerWorkspacePVCName(workspace.Status.WorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				config.WorkspaceIDLabel: workspace.Status.WorkspaceId,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					"storage": pvcStorageQuantity,
+				},
+			},
+			StorageClassName: config.ControllerCfg.GetPVCStorageClassName(),
+		},
+	}
+	err = controllerutil.SetControllerReference(workspace, pvc, clusterAPI.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return pvc, nil
+}
+
+// getWorkspaceVolume returns the volume holding workspace files, according to the workspace's storage strategy. The
+// volume is always named after the common PVC, as that name is used in volume mounts contributed by components.
+func getWorkspaceVolume(workspace *v1alpha1.Workspace) corev1.Volume {
+	volume := corev1.Volume{
+		Name: config.ControllerCfg.GetWorkspacePVCName(),
+	}
+	switch GetStorageStrategy(workspace) {
+	case config.EphemeralStorageStrategy:
+		volume.VolumeSource = corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		}
+	case config.PerWorkspaceStorageStrategy:
+		volume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: getPerWorkspacePVCName(workspace.Status.WorkspaceId),
+			},
+		}
+	default:
+		volume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: config.ControllerCfg.GetWorkspacePVCName(),
+			},
+		}
+	}
+	return volume
+}
+
+func getPerWorkspacePVCName(workspaceId string) string {
+	return fmt.Sprintf("%s-%s", config.ControllerCfg.GetWorkspacePVCName(), workspaceId)
+}
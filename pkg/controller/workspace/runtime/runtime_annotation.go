@@ -2,7 +2,6 @@ package runtime
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 )
 
@@ -33,12 +32,10 @@ func getMachinesAnnotation(components []v1alpha1.ComponentDescription, endpoints
 			servers := map[string]v1alpha1.CheWorkspaceServer{}
 			// TODO: This is likely not a good choice for matching, since it'll fail if container name does not match an endpoint key
 			for _, endpoint := range endpoints[containerName] {
-				protocol := endpoint.Attributes[v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE]
-
 				servers[endpoint.Name] = v1alpha1.CheWorkspaceServer{
 					Attributes: endpoint.Attributes,
 					Status:     v1alpha1.RunningServerStatus, // TODO: This is just set so the circles are green
-					URL:        fmt.Sprintf("%s://%s", protocol, endpoint.Url), // TODO: This could potentially be done when the endpoint is created (i.e. include protocol in endpoint.Url)
+					URL:        endpoint.Url,
 				}
 			}
 			machines[containerName] = v1alpha1.CheWorkspaceMachine{
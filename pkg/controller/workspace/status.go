@@ -0,0 +1,140 @@
+package workspace
+
+import (
+	"context"
+	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/provision"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	conditionReasonReady             = "Ready"
+	conditionReasonInProgress        = "InProgress"
+	conditionReasonProvisioningError = "ProvisioningError"
+)
+
+// currentStatus stores the workspace status observed during a single reconcile. It is written to the cluster
+// once the reconcile finishes.
+type currentStatus struct {
+	// Conditions observed in this reconcile; stages that were not reached keep their previous condition.
+	Conditions []workspacev1alpha1.WorkspaceCondition
+	Phase      workspacev1alpha1.WorkspaceStatusType
+}
+
+// checkStage records the result of a provisioning stage as a condition of type condType and returns whether
+// reconciling should continue to the next stage. Errors move the workspace to the Failed phase. Stages waiting on the
+// cluster (e.g. for a deployment to become ready) move a running workspace back to the Starting phase, unless another
+// phase was already set in this reconcile; stages that only requeue after creating or updating objects, or after a
+// conflict, leave the phase unchanged.
+func (s *currentStatus) checkStage(condType workspacev1alpha1.WorkspaceConditionType, stage provision.ProvisioningStatus, waitingMessage string) bool {
+	switch {
+	case stage.Err != nil && !errors.IsConflict(stage.Err):
+		s.setCondition(condType, corev1.ConditionFalse, conditionReasonProvisioningError, stage.Err.Error())
+		s.Phase = workspacev1alpha1.WorkspaceStatusFailed
+	case !stage.Continue:
+		s.setCondition(condType, corev1.ConditionFalse, conditionReasonInProgress, waitingMessage)
+		if stage.Err == nil && !stage.Requeue && s.Phase == "" {
+			s.Phase = workspacev1alpha1.WorkspaceStatusStarting
+		}
+	default:
+		s.setCondition(condType, corev1.ConditionTrue, conditionReasonReady, "")
+	}
+	return stage.Continue
+}
+
+func (s *currentStatus) setCondition(condType workspacev1alpha1.WorkspaceConditionType, status corev1.ConditionStatus, reason, message string) {
+	for idx := range s.Conditions {
+		if s.Conditions[idx].Type == condType {
+			s.Conditions[idx].Status = status
+			s.Conditions[idx].Reason = reason
+			s.Conditions[idx].Message = message
+			return
+		}
+	}
+	s.Conditions = append(s.Conditions, workspacev1alpha1.WorkspaceCondition{
+		Type:    condType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// updateWorkspaceStatus merges the status gathered during reconcile into the workspace's status and updates the
+// cluster object if anything changed. The reconcile result and error passed in are returned unless the update fails.
+// clusterStatus is the workspace status as read at the start of the reconcile.
+func (r *ReconcileWorkspace) updateWorkspaceStatus(
+	workspace *workspacev1alpha1.Workspace,
+	clusterStatus *workspacev1alpha1.WorkspaceStatus,
+	status *currentStatus,
+	logger logr.Logger,
+	reconcileResult reconcile.Result,
+	reconcileError error) (reconcile.Result, error) {
+
+	newStatus := workspace.Status.DeepCopy()
+	if status.Phase != "" {
+		newStatus.Status = status.Phase
+	}
+	if newStatus.Status == workspacev1alpha1.WorkspaceStatusStopped {
+		newStatus.Condition = nil
+	} else {
+		newStatus.Condition = mergeConditions(workspace.Status.Condition, status.Conditions)
+	}
+
+	if cmp.Equal(*clusterStatus, *newStatus) && cmp.Equal(workspace.Status, *newStatus) {
+		return reconcileResult, reconcileError
+	}
+
+	workspace.Status = *newStatus
+	err := r.client.Status().Update(context.TODO(), workspace)
+	if err != nil {
+		if errors.IsConflict(err) {
+			logger.Info("Failed to update workspace status due to conflict; retrying")
+			return reconcile.Result{Requeue: true}, reconcileError
+		}
+		if errors.IsNotFound(err) {
+			// Workspace was deleted after its finalizers were removed
+			return reconcileResult, reconcileError
+		}
+		logger.Error(err, "Failed to update workspace status")
+		if reconcileError == nil {
+			return reconcileResult, err
+		}
+	}
+	return reconcileResult, reconcileError
+}
+
+// mergeConditions applies the conditions observed in the current reconcile on top of the existing conditions,
+// preserving LastTransitionTime for conditions whose status did not change.
+func mergeConditions(existing, observed []workspacev1alpha1.WorkspaceCondition) []workspacev1alpha1.WorkspaceCondition {
+	var merged []workspacev1alpha1.WorkspaceCondition
+	for _, condition := range existing {
+		merged = append(merged, *condition.DeepCopy())
+	}
+
+	now := metav1.Now()
+	for _, condition := range observed {
+		found := false
+		for idx := range merged {
+			if merged[idx].Type != condition.Type {
+				continue
+			}
+			found = true
+			if merged[idx].Status != condition.Status {
+				merged[idx].LastTransitionTime = now
+			}
+			merged[idx].Status = condition.Status
+			merged[idx].Reason = condition.Reason
+			merged[idx].Message = condition.Message
+		}
+		if !found {
+			condition.LastTransitionTime = now
+			merged = append(merged, condition)
+		}
+	}
+	return merged
+}
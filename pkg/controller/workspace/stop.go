@@ -0,0 +1,31 @@
+package workspace
+
+import (
+	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/provision"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// stopWorkspace scales down the workspace deployment and removes its routing. Components, the workspace ServiceAccount
+// and all data on the workspace PVC are kept, so that setting `started: true` restarts the workspace from the same state.
+func (r *ReconcileWorkspace) stopWorkspace(
+	workspace *workspacev1alpha1.Workspace,
+	status *currentStatus,
+	clusterAPI provision.ClusterAPI) (reconcile.Result, error) {
+
+	deploymentStatus := provision.ScaleDeploymentToZero(workspace, clusterAPI)
+	if !deploymentStatus.Continue {
+		clusterAPI.Logger.Info("Waiting on workspace deployment to scale down")
+		return reconcile.Result{Requeue: deploymentStatus.Requeue}, deploymentStatus.Err
+	}
+
+	routingStatus := provision.DeleteRouting(workspace, clusterAPI)
+	if !routingStatus.Continue {
+		clusterAPI.Logger.Info("Waiting on workspace routing to be removed")
+		return reconcile.Result{Requeue: routingStatus.Requeue}, routingStatus.Err
+	}
+
+	status.Phase = workspacev1alpha1.WorkspaceStatusStopped
+	clusterAPI.Logger.Info("Workspace stopped")
+	return reconcile.Result{}, nil
+}
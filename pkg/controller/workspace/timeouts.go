@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	stoppedByIdleTimeout = "idle-timeout"
+	stoppedByMaxRunTime  = "max-run-time"
+)
+
+// checkWorkspaceTimeouts stops a running workspace if it has been idle for longer than its idle timeout, or running for
+// longer than its maximum run time. If neither limit is reached, the returned result requeues the workspace for when
+// the earliest one would be.
+func (r *ReconcileWorkspace) checkWorkspaceTimeouts(workspace *workspacev1alpha1.Workspace, logger logr.Logger) (reconcile.Result, error) {
+	idleTimeout, err := getWorkspaceDuration(workspace, config.WorkspaceIdleTimeoutAnnotation, config.ControllerCfg.GetWorkspaceIdleTimeout())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	maxRunTime, err := getWorkspaceDuration(workspace, config.WorkspaceMaxRunTimeAnnotation, config.ControllerCfg.GetWorkspaceMaxRunTime())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if idleTimeout == 0 && maxRunTime == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	now := time.Now()
+	runningSince := getRunningSince(workspace, now)
+	var requeueAfter time.Duration
+
+	if maxRunTime > 0 {
+		remaining := runningSince.Add(maxRunTime).Sub(now)
+		if remaining <= 0 {
+			logger.Info("Workspace reached its maximum run time; stopping", "maxRunTime", maxRunTime.String())
+			return reconcile.Result{Requeue: true}, r.stopWorkspaceBy(workspace, stoppedByMaxRunTime)
+		}
+		requeueAfter = remaining
+	}
+
+	if idleTimeout > 0 {
+		lastActivity, err := getLastActivity(workspace, runningSince)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		remaining := lastActivity.Add(idleTimeout).Sub(now)
+		if remaining <= 0 {
+			logger.Info("Workspace is idle; stopping", "idleTimeout", idleTimeout.String())
+			return reconcile.Result{Requeue: true}, r.stopWorkspaceBy(workspace, stoppedByIdleTimeout)
+		}
+		if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// stopWorkspaceBy sets `started: false` on the workspace, recording the reason in an annotation.
+func (r *ReconcileWorkspace) stopWorkspaceBy(workspace *workspacev1alpha1.Workspace, reason string) error {
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[config.WorkspaceStoppedByAnnotation] = reason
+	workspace.Spec.Started = false
+	return r.client.Update(context.TODO(), workspace)
+}
+
+// clearStoppedBy removes the reason the controller stopped the workspace once the workspace is started again. Returns
+// true if the workspace was updated.
+func (r *ReconcileWorkspace) clearStoppedBy(workspace *workspacev1alpha1.Workspace) (updated bool, err error) {
+	if _, ok := workspace.Annotations[config.WorkspaceStoppedByAnnotation]; !ok {
+		return false, nil
+	}
+	delete(workspace.Annotations, config.WorkspaceStoppedByAnnotation)
+	return true, r.client.Update(context.TODO(), workspace)
+}
+
+// getWorkspaceDuration reads a duration from the workspace's annotation, falling back to the controller config value.
+// Empty values are treated as zero.
+func getWorkspaceDuration(workspace *workspacev1alpha1.Workspace, annotation, configValue string) (time.Duration, error) {
+	value := configValue
+	if annotationValue, ok := workspace.Annotations[annotation]; ok {
+		value = annotationValue
+	}
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s' for %s: %w", value, annotation, err)
+	}
+	return duration, nil
+}
+
+// getRunningSince returns the time the workspace deployment became ready, or now if it only just became ready.
+func getRunningSince(workspace *workspacev1alpha1.Workspace, now time.Time) time.Time {
+	for _, condition := range workspace.Status.Condition {
+		if condition.Type == workspacev1alpha1.WorkspaceConditionDeploymentReady &&
+			condition.Status == corev1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return now
+}
+
+// getLastActivity returns the time of the last activity recorded on the workspace. Activity from before the workspace
+// was started is ignored.
+func getLastActivity(workspace *workspacev1alpha1.Workspace, runningSince time.Time) (time.Time, error) {
+	lastActivityStr, ok := workspace.Annotations[config.WorkspaceLastActivityAnnotation]
+	if !ok {
+		return runningSince, nil
+	}
+	lastActivity, err := time.Parse(time.RFC3339, lastActivityStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s annotation '%s': %w", config.WorkspaceLastActivityAnnotation, lastActivityStr, err)
+	}
+	if lastActivity.Before(runningSince) {
+		return runningSince, nil
+	}
+	return lastActivity, nil
+}
@@ -6,21 +6,26 @@ import (
 	"github.com/che-incubator/che-workspace-operator/internal/cluster"
 	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 	"github.com/che-incubator/che-workspace-operator/pkg/config"
-	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/prerequisites"
 	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/provision"
 	wsRuntime "github.com/che-incubator/che-workspace-operator/pkg/controller/workspace/runtime"
 	"github.com/google/uuid"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	origLog "log"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	"strings"
@@ -88,6 +93,56 @@ func add(mgr manager.Manager, r *ReconcileWorkspace) error {
 		OwnerType:    &workspacev1alpha1.Workspace{},
 	})
 
+	// Watch for workspace storage cleanup jobs and requeue the owner workspace
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &workspacev1alpha1.Workspace{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the workspace's Roles and RoleBindings and requeue the owner workspace
+	err = c.Watch(&source.Kind{Type: &rbacv1.Role{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &workspacev1alpha1.Workspace{},
+	})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &rbacv1.RoleBinding{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &workspacev1alpha1.Workspace{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for workspace pods being created or deleted, so that the workspace's Role is updated with the new pod
+	// names. Pods are owned by the deployment's ReplicaSets, so they are mapped to the workspace by label.
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			workspaceName, ok := obj.Meta.GetLabels()[config.WorkspaceNameLabel]
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      workspaceName,
+						Namespace: obj.Meta.GetNamespace(),
+					},
+				},
+			}
+		}),
+	}, predicate.Funcs{
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	})
+	if err != nil {
+		return err
+	}
+
 	// Check if we're running on OpenShift
 	isOS, err := cluster.IsOpenShift()
 	if err != nil {
@@ -121,7 +176,7 @@ func (r *ReconcileWorkspace) Write(p []byte) (n int, err error) {
 
 // Reconcile reads that state of the cluster for a Workspace object and makes changes based on the state read
 // and what is in the Workspace.Spec
-func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcileResult reconcile.Result, err error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling Workspace")
 	clusterAPI := provision.ClusterAPI{
@@ -132,7 +187,7 @@ func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcile.Res
 
 	// Fetch the Workspace instance
 	workspace := &workspacev1alpha1.Workspace{}
-	err := r.client.Get(context.TODO(), request.NamespacedName, workspace)
+	err = r.client.Get(context.TODO(), request.NamespacedName, workspace)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -144,12 +199,12 @@ func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcile.Res
 		return reconcile.Result{}, err
 	}
 
-	// TODO: The rolebindings here are created namespace-wide; find a way to limit this, given that each workspace
-	// needs a new serviceAccount
-	err = prerequisites.CheckPrerequisites(workspace, r.client, reqLogger)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
+	// Status observed during this reconcile is written to the cluster once it completes
+	clusterStatus := workspace.Status.DeepCopy()
+	reconcileStatus := &currentStatus{}
+	defer func() {
+		reconcileResult, err = r.updateWorkspaceStatus(workspace, clusterStatus, reconcileStatus, reqLogger, reconcileResult, err)
+	}()
 
 	// Ensure workspaceID is set.
 	if workspace.Status.WorkspaceId == "" {
@@ -159,10 +214,33 @@ func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcile.Res
 		}
 		workspace.Status.WorkspaceId = workspaceId
 	}
+	// Ensure the storage strategy is recorded, so that configuration changes do not affect existing workspaces
+	storageStrategyStatus := provision.SyncStorageStrategy(workspace, clusterAPI)
+	if !storageStrategyStatus.Continue {
+		return reconcile.Result{Requeue: storageStrategyStatus.Requeue}, storageStrategyStatus.Err
+	}
+
+	if workspace.DeletionTimestamp != nil {
+		return r.finalizeWorkspace(workspace, reconcileStatus, clusterAPI)
+	}
+	if updated, err := r.ensureFinalizer(workspace); updated || err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+
+	if !workspace.Spec.Started {
+		return r.stopWorkspace(workspace, reconcileStatus, clusterAPI)
+	}
+	if updated, err := r.clearStoppedBy(workspace); updated || err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+	// A running workspace only moves back to Starting if one of its stages regresses; see checkStage
+	if workspace.Status.Status != workspacev1alpha1.WorkspaceStatusStarted {
+		reconcileStatus.Phase = workspacev1alpha1.WorkspaceStatusStarting
+	}
 
 	// Step one: Create components, and wait for their states to be ready.
 	componentsStatus := provision.SyncComponentsToCluster(workspace, clusterAPI)
-	if !componentsStatus.Continue {
+	if !reconcileStatus.checkStage(workspacev1alpha1.WorkspaceConditionComponentsReady, componentsStatus.ProvisioningStatus, "Waiting on components to be ready") {
 		reqLogger.Info("Waiting on components to be ready")
 		return reconcile.Result{Requeue: componentsStatus.Requeue}, componentsStatus.Err
 	}
@@ -173,7 +251,11 @@ func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcile.Res
 
 	// Step two: Create routing, and wait for routing to be ready
 	routingStatus := provision.SyncRoutingToCluster(workspace, componentDescriptions, clusterAPI)
-	if !routingStatus.Continue {
+	routingWaitingMessage := "Waiting on workspace routing to be ready"
+	if routingStatus.Message != "" {
+		routingWaitingMessage = fmt.Sprintf("%s: %s", routingWaitingMessage, routingStatus.Message)
+	}
+	if !reconcileStatus.checkStage(workspacev1alpha1.WorkspaceConditionRoutingReady, routingStatus.ProvisioningStatus, routingWaitingMessage) {
 		reqLogger.Info("Waiting on routing to be ready")
 		return reconcile.Result{Requeue: routingStatus.Requeue}, routingStatus.Err
 	}
@@ -202,21 +284,37 @@ func (r *ReconcileWorkspace) Reconcile(request reconcile.Request) (reconcile.Res
 		saAnnotations = routingPodAdditions.ServiceAccountAnnotations
 	}
 	serviceAcctStatus := provision.SyncServiceAccount(workspace, saAnnotations, clusterAPI)
-	if !serviceAcctStatus.Continue{
+	if !reconcileStatus.checkStage(workspacev1alpha1.WorkspaceConditionServiceAccountReady, serviceAcctStatus.ProvisioningStatus, "Waiting for workspace ServiceAccount") {
 		reqLogger.Info("Waiting for workspace ServiceAccount")
 		return reconcile.Result{Requeue: serviceAcctStatus.Requeue}, serviceAcctStatus.Err
 	}
 	serviceAcctName := serviceAcctStatus.ServiceAccountName
 
+	// Grant the workspace ServiceAccount access to the workspace's Workspace object before its pods are created. Access
+	// to the pods is granted once they exist.
+	rbacStatus := provision.SyncRBACToCluster(workspace, serviceAcctName, clusterAPI)
+	if !reconcileStatus.checkStage(workspacev1alpha1.WorkspaceConditionRBACReady, rbacStatus, "Waiting for workspace RBAC") {
+		reqLogger.Info("Waiting for workspace RBAC")
+		return reconcile.Result{Requeue: rbacStatus.Requeue}, rbacStatus.Err
+	}
+
+	// Step 4.5: Provision workspace storage according to storage strategy
+	storageStatus := provision.SyncStorageToCluster(workspace, clusterAPI)
+	if !reconcileStatus.checkStage(workspacev1alpha1.WorkspaceConditionStorageReady, storageStatus, "Waiting for workspace storage") {
+		reqLogger.Info("Waiting for workspace storage")
+		return reconcile.Result{Requeue: storageStatus.Requeue}, storageStatus.Err
+	}
+
 	// Step five: Create deployment and wait for it to be ready
 	deploymentStatus := provision.SyncDeploymentToCluster(workspace, podAdditions, serviceAcctName, clusterAPI)
-	if !deploymentStatus.Continue {
+	if !reconcileStatus.checkStage(workspacev1alpha1.WorkspaceConditionDeploymentReady, deploymentStatus.ProvisioningStatus, "Waiting on deployment to be ready") {
 		reqLogger.Info("Waiting on deployment to be ready")
 		return reconcile.Result{Requeue: deploymentStatus.Requeue}, deploymentStatus.Err
 	}
 
+	reconcileStatus.Phase = workspacev1alpha1.WorkspaceStatusStarted
 	reqLogger.Info("Everything ready :)")
-	return reconcile.Result{}, nil
+	return r.checkWorkspaceTimeouts(workspace, reqLogger)
 }
 
 func getWorkspaceId(instance *workspacev1alpha1.Workspace) (string, error) {
@@ -0,0 +1,41 @@
+package workspacerouting
+
+import (
+	"context"
+	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/go-logr/logr"
+	userV1 "github.com/openshift/api/user/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getAllowedUsers returns the users allowed to access the workspace in addition to its creator. Groups in the
+// routing's access list are expanded to their members; as groups are an OpenShift concept, they are ignored on
+// Kubernetes. Groups that do not exist are skipped.
+//
+// Groups are cluster-scoped, so they are read directly from the API server rather than through the (possibly
+// namespaced) cache.
+func (r *ReconcileWorkspaceRouting) getAllowedUsers(routing *workspacev1alpha1.WorkspaceRouting, logger logr.Logger) ([]string, error) {
+	users := append([]string{}, routing.Spec.Access.Users...)
+	if len(routing.Spec.Access.Groups) == 0 {
+		return users, nil
+	}
+	if !config.ControllerCfg.IsOpenShift() {
+		logger.Info("Ignoring groups in workspace access list; groups are only supported on OpenShift")
+		return users, nil
+	}
+	for _, groupName := range routing.Spec.Access.Groups {
+		group := &userV1.Group{}
+		err := r.apiReader.Get(context.TODO(), types.NamespacedName{Name: groupName}, group)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				logger.Info("Group in workspace access list does not exist", "group", groupName)
+				continue
+			}
+			return nil, err
+		}
+		users = append(users, group.Users...)
+	}
+	return users, nil
+}
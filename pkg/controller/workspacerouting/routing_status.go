@@ -0,0 +1,191 @@
+package workspacerouting
+
+import (
+	"net/url"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/common"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspacerouting/solvers"
+	routeV1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	endpointReasonIngressPending  = "IngressPending"
+	endpointReasonRoutePending    = "RoutePending"
+	endpointReasonRouteRejected   = "RouteRejected"
+	endpointReasonServicePending  = "ServiceAddressPending"
+	endpointReasonServiceConflict = "ServiceConflict"
+)
+
+// getEndpointStatuses returns the readiness of each exposed endpoint, by name. An endpoint is ready once every
+// ingress and route for its host has been admitted: ingresses once the ingress controller reports a load balancer
+// address, and routes once a router has admitted them. Endpoints that are not exposed through ingresses or routes,
+// e.g. those of the istio routing class, are ready as soon as their objects are created. Endpoints exposed through
+// external services are ready once the cluster has assigned an address to their service.
+func (r *ReconcileWorkspaceRouting) getEndpointStatuses(
+	routing *v1alpha1.WorkspaceRouting,
+	routingObjects solvers.RoutingObjects) (map[string]v1alpha1.EndpointStatus, error) {
+
+	hostStatuses := map[string]v1alpha1.EndpointStatus{}
+	if len(routingObjects.Ingresses) > 0 {
+		var err error
+		if r.ingressV1 {
+			err = r.addIngressV1HostStatuses(routing, hostStatuses)
+		} else {
+			err = r.addIngressHostStatuses(routing, hostStatuses)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(routingObjects.Routes) > 0 {
+		clusterRoutes, err := r.getClusterRoutes(routing)
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range clusterRoutes {
+			addHostStatus(hostStatuses, route.Spec.Host, getRouteStatus(route))
+		}
+	}
+
+	endpointStatuses := map[string]v1alpha1.EndpointStatus{}
+	for _, machineEndpoints := range routingObjects.ExposedEndpoints {
+		for _, endpoint := range machineEndpoints {
+			if endpoint.Url == "" {
+				endpointStatuses[endpoint.Name] = v1alpha1.EndpointStatus{
+					Ready:   false,
+					Reason:  endpointReasonServicePending,
+					Message: "service for endpoint " + endpoint.Name + " has not been assigned an external address",
+				}
+				continue
+			}
+			var host string
+			if endpointURL, err := url.Parse(endpoint.Url); err == nil {
+				host = endpointURL.Hostname()
+			}
+			status, ok := hostStatuses[host]
+			if !ok {
+				status = v1alpha1.EndpointStatus{Ready: true}
+			}
+			endpointStatuses[endpoint.Name] = status
+		}
+	}
+	return endpointStatuses, nil
+}
+
+func (r *ReconcileWorkspaceRouting) addIngressHostStatuses(routing *v1alpha1.WorkspaceRouting, hostStatuses map[string]v1alpha1.EndpointStatus) error {
+	clusterIngresses, err := r.getClusterIngresses(routing)
+	if err != nil {
+		return err
+	}
+	for _, ingress := range clusterIngresses {
+		status := getIngressStatus(ingress.Name, len(ingress.Status.LoadBalancer.Ingress) > 0)
+		for _, rule := range ingress.Spec.Rules {
+			addHostStatus(hostStatuses, rule.Host, status)
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileWorkspaceRouting) addIngressV1HostStatuses(routing *v1alpha1.WorkspaceRouting, hostStatuses map[string]v1alpha1.EndpointStatus) error {
+	clusterIngresses, err := r.getClusterIngressesV1(routing)
+	if err != nil {
+		return err
+	}
+	for _, ingress := range clusterIngresses {
+		lbIngresses, _, _ := unstructured.NestedSlice(ingress.Object, "status", "loadBalancer", "ingress")
+		status := getIngressStatus(ingress.GetName(), len(lbIngresses) > 0)
+		rules, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+		for _, rule := range rules {
+			ruleMap, ok := rule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			host, _, _ := unstructured.NestedString(ruleMap, "host")
+			addHostStatus(hostStatuses, host, status)
+		}
+	}
+	return nil
+}
+
+// addServiceConflictStatuses marks discoverable endpoints as not ready if their service could not be created as a
+// service with the same name already exists, e.g. for a discoverable endpoint with the same name in another workspace
+func addServiceConflictStatuses(
+	endpointStatuses map[string]v1alpha1.EndpointStatus,
+	endpoints map[string][]v1alpha1.Endpoint,
+	conflicts []string) {
+
+	for _, machineEndpoints := range endpoints {
+		for _, endpoint := range machineEndpoints {
+			if endpoint.Attributes[v1alpha1.DISCOVERABLE_ATTRIBUTE] != "true" {
+				continue
+			}
+			serviceName := common.EndpointName(endpoint.Name)
+			for _, conflict := range conflicts {
+				if conflict == serviceName {
+					endpointStatuses[endpoint.Name] = v1alpha1.EndpointStatus{
+						Ready:   false,
+						Reason:  endpointReasonServiceConflict,
+						Message: "service " + serviceName + " already exists and does not belong to this workspace",
+					}
+				}
+			}
+		}
+	}
+}
+
+func getIngressStatus(name string, hasAddress bool) v1alpha1.EndpointStatus {
+	if !hasAddress {
+		return v1alpha1.EndpointStatus{
+			Ready:   false,
+			Reason:  endpointReasonIngressPending,
+			Message: "ingress " + name + " has not been assigned an address by the ingress controller",
+		}
+	}
+	return v1alpha1.EndpointStatus{Ready: true}
+}
+
+// getRouteStatus returns the status of route. A route is ready if any router admitted it, and rejected if no router
+// admitted it and at least one router rejected it, e.g. as its host is already claimed.
+func getRouteStatus(route routeV1.Route) v1alpha1.EndpointStatus {
+	var rejected *routeV1.RouteIngressCondition
+	for _, routeIngress := range route.Status.Ingress {
+		for idx, condition := range routeIngress.Conditions {
+			if condition.Type != routeV1.RouteAdmitted {
+				continue
+			}
+			switch condition.Status {
+			case corev1.ConditionTrue:
+				return v1alpha1.EndpointStatus{Ready: true}
+			case corev1.ConditionFalse:
+				rejected = &routeIngress.Conditions[idx]
+			}
+		}
+	}
+	if rejected != nil {
+		reason := endpointReasonRouteRejected
+		if rejected.Reason != "" {
+			reason = rejected.Reason
+		}
+		return v1alpha1.EndpointStatus{
+			Ready:   false,
+			Reason:  reason,
+			Message: "route " + route.Name + " was rejected: " + rejected.Message,
+		}
+	}
+	return v1alpha1.EndpointStatus{
+		Ready:   false,
+		Reason:  endpointReasonRoutePending,
+		Message: "route " + route.Name + " has not been admitted by a router",
+	}
+}
+
+// addHostStatus records status for host, unless host already has a status that is not ready
+func addHostStatus(hostStatuses map[string]v1alpha1.EndpointStatus, host string, status v1alpha1.EndpointStatus) {
+	if existing, ok := hostStatuses[host]; ok && !existing.Ready {
+		return
+	}
+	hostStatuses[host] = status
+}
@@ -4,24 +4,30 @@ import (
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 )
 
-var ingressAnnotations = map[string]string{
-	"kubernetes.io/ingress.class":                "nginx",
-	"nginx.ingress.kubernetes.io/rewrite-target": "/",
-	"nginx.ingress.kubernetes.io/ssl-redirect":   "false",
-}
-
 type BasicSolver struct {}
 
 var _ RoutingSolver = (*BasicSolver)(nil)
 
-func (s *BasicSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) RoutingObjects {
+func (s *BasicSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) (RoutingObjects, error) {
 	services := getServicesForEndpoints(spec.Endpoints, workspaceMeta)
-	ingresses, exposedEndpoints := getIngressesForSpec(spec.Endpoints, workspaceMeta)
+	publicEndpoints, internalEndpoints := splitInternalEndpoints(spec.Endpoints)
+	httpEndpoints, externalEndpoints := splitNonHTTPEndpoints(publicEndpoints)
+	ingresses, exposedEndpoints, err := getIngressesForSpec(httpEndpoints, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	externalServices, err := getExternalServices(externalEndpoints, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	services = append(services, externalServices...)
+	addInternalExposedEndpoints(exposedEndpoints, internalEndpoints, workspaceMeta)
+	addExternalExposedEndpoints(exposedEndpoints, externalEndpoints, workspaceMeta)
 
 	return RoutingObjects{
 		Services: services,
 		Ingresses: ingresses,
 		ExposedEndpoints: exposedEndpoints,
-	}
+	}, nil
 }
 
@@ -4,11 +4,20 @@ import (
 	"fmt"
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 	"github.com/che-incubator/che-workspace-operator/pkg/common"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sort"
 	"strconv"
+	"strings"
+)
+
+const (
+	certManagerClusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
+	certManagerIssuerAnnotation        = "cert-manager.io/issuer"
+	nginxSSLRedirectAnnotation         = "nginx.ingress.kubernetes.io/ssl-redirect"
 )
 
 type WorkspaceMetadata struct {
@@ -16,49 +25,85 @@ type WorkspaceMetadata struct {
 	Namespace       string
 	PodSelector     map[string]string
 	IngressGlobalDomain string
+	// CookieSecretRotation is an opaque value; changing it causes secrets generated for the workspace to be regenerated
+	CookieSecretRotation string
+	// RoutingClass is the routing class of the workspace's routing
+	RoutingClass v1alpha1.WorkspaceRoutingClass
+	// Creator is the name of the user that created the workspace; empty if unknown
+	Creator string
+	// AllowedUsers are the names of users, other than the creator, that may access the workspace. Members of groups
+	// in the routing's access list are included.
+	AllowedUsers []string
+	// NodeAddress is the address at which endpoints exposed through NodePort services are reachable; if empty, the
+	// ingress global domain is used
+	NodeAddress string
 }
 
+// getServicesForEndpoints returns the workspace's internal service, which exposes all endpoints and is the backend
+// for ingresses and routes, and a service for each discoverable endpoint. Discoverable endpoints' services are named
+// after the endpoint, so that other containers can reach them by the endpoint's name.
 func getServicesForEndpoints(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) []corev1.Service {
 	var servicePorts []corev1.ServicePort
+	discoverablePorts := map[string][]corev1.ServicePort{}
 	for _, machineEndpoints := range endpoints {
 		for _, endpoint := range machineEndpoints {
-
-			if endpoint.Attributes[v1alpha1.DISCOVERABLE_ATTRIBUTE] != "true" {
-				//continue // TODO: Unclear how this is supposed to work?
-			}
-			servicePorts = append(servicePorts, corev1.ServicePort{
+			servicePort := corev1.ServicePort{
 				Name:       common.EndpointName(endpoint.Name),
-				Protocol:   corev1.ProtocolTCP,
+				Protocol:   common.EndpointProtocol(endpoint),
 				Port:       int32(endpoint.Port),
 				TargetPort: intstr.FromInt(int(endpoint.Port)),
-			})
+			}
+			servicePorts = append(servicePorts, servicePort)
+			if endpoint.Attributes[v1alpha1.DISCOVERABLE_ATTRIBUTE] == "true" {
+				serviceName := common.EndpointName(endpoint.Name)
+				discoverablePorts[serviceName] = append(discoverablePorts[serviceName], servicePort)
+			}
 		}
 	}
 
-	return []corev1.Service{
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "service-" + workspaceMeta.WorkspaceId, // TODO?
-				Namespace: workspaceMeta.Namespace,
-				Labels: map[string]string{
-					"app": workspaceMeta.WorkspaceId,
-				},
-			},
-			Spec: corev1.ServiceSpec{
-				Ports:    servicePorts,
-				Selector: workspaceMeta.PodSelector,
-				Type:     corev1.ServiceTypeClusterIP,
+	services := []corev1.Service{
+		getService(common.ServiceName(workspaceMeta.WorkspaceId), servicePorts, workspaceMeta), // TODO?
+	}
+	var discoverableNames []string
+	for serviceName := range discoverablePorts {
+		discoverableNames = append(discoverableNames, serviceName)
+	}
+	sort.Strings(discoverableNames)
+	for _, serviceName := range discoverableNames {
+		services = append(services, getService(serviceName, discoverablePorts[serviceName], workspaceMeta))
+	}
+	return services
+}
+
+func getService(name string, ports []corev1.ServicePort, workspaceMeta WorkspaceMetadata) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: workspaceMeta.Namespace,
+			Labels: map[string]string{
+				"app": workspaceMeta.WorkspaceId,
 			},
 		},
+		Spec: corev1.ServiceSpec{
+			Ports:    ports,
+			Selector: workspaceMeta.PodSelector,
+			Type:     corev1.ServiceTypeClusterIP,
+		},
 	}
 }
 
-func getIngressesForSpec(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) ([]v1beta1.Ingress, map[string][]v1alpha1.ExposedEndpoint) {
+// getIngressesForSpec returns an ingress for each endpoint. Ingresses are returned as extensions/v1beta1 objects
+// without an ingress class; the routing controller converts them to the Ingress API served by the cluster.
+func getIngressesForSpec(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) ([]v1beta1.Ingress, map[string][]v1alpha1.ExposedEndpoint, error) {
 	var ingresses []v1beta1.Ingress
 	exposedEndpoints := map[string][]v1alpha1.ExposedEndpoint{}
 
 	for machineName, machineEndpoints := range endpoints {
 		for _, endpoint := range machineEndpoints {
+			ingressAnnotations, err := getEndpointIngressAnnotations(endpoint, workspaceMeta)
+			if err != nil {
+				return nil, nil, err
+			}
 			if endpoint.Attributes[v1alpha1.PUBLIC_ENDPOINT_ATTRIBUTE] != "true" {
 				//continue // TODO: Unclear how this is supposed to work?
 			}
@@ -70,7 +115,7 @@ func getIngressesForSpec(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta
 			endpointName := common.EndpointName(endpoint.Name)
 			ingressHostname := fmt.Sprintf("%s-%s-%s.%s",
 				workspaceMeta.WorkspaceId, endpointName, strconv.FormatInt(endpoint.Port, 10), workspaceMeta.IngressGlobalDomain)
-			ingresses = append(ingresses, v1beta1.Ingress{
+			ingress := v1beta1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      fmt.Sprintf("%s-%s", workspaceMeta.WorkspaceId, endpointName),
 					Namespace: workspaceMeta.Namespace,
@@ -88,7 +133,7 @@ func getIngressesForSpec(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta
 									Paths: []v1beta1.HTTPIngressPath{
 										{
 											Backend: v1beta1.IngressBackend{
-												ServiceName: "service-" + workspaceMeta.WorkspaceId, // TODO: Copied from service func above
+												ServiceName: common.ServiceName(workspaceMeta.WorkspaceId),
 												ServicePort: targetEndpoint,
 											},
 										},
@@ -98,13 +143,141 @@ func getIngressesForSpec(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta
 						},
 					},
 				},
+			}
+			tlsEnabled := endpoint.Attributes[v1alpha1.SECURE_ENDPOINT_ATTRIBUTE] == "true" && addIngressTLS(&ingress, ingressHostname)
+			ingresses = append(ingresses, ingress)
+			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], v1alpha1.ExposedEndpoint{
+				Name:        endpoint.Name,
+				Url:         getEndpointURL(ingressHostname, "/", endpoint.Attributes, tlsEnabled),
+				InternalUrl: getInternalURL(endpoint, workspaceMeta),
+				Attributes:  endpoint.Attributes,
 			})
+		}
+	}
+	return ingresses, exposedEndpoints, nil
+}
+
+// addIngressTLS configures TLS for host on ingress, using the wildcard certificate Secret from the controller config
+// if set, or requesting a certificate from the configured cert-manager issuer otherwise. Returns false if neither is
+// configured, in which case ingress is not changed.
+func addIngressTLS(ingress *v1beta1.Ingress, host string) bool {
+	secretName := config.ControllerCfg.GetIngressTLSSecretName()
+	if secretName == "" {
+		issuer := config.ControllerCfg.GetIngressTLSCertManagerIssuer()
+		if issuer == "" {
+			return false
+		}
+		if ingress.Annotations == nil {
+			ingress.Annotations = map[string]string{}
+		}
+		if config.ControllerCfg.GetIngressTLSCertManagerIssuerKind() == "Issuer" {
+			ingress.Annotations[certManagerIssuerAnnotation] = issuer
+		} else {
+			ingress.Annotations[certManagerClusterIssuerAnnotation] = issuer
+		}
+		// cert-manager stores the issued certificate in this secret
+		secretName = ingress.Name + "-tls"
+	}
+	// Default ingress annotations disable redirecting to https, as most endpoints are served over http only
+	if _, ok := ingress.Annotations[nginxSSLRedirectAnnotation]; ok {
+		ingress.Annotations[nginxSSLRedirectAnnotation] = "true"
+	}
+	ingress.Spec.TLS = []v1beta1.IngressTLS{
+		{
+			Hosts:      []string{host},
+			SecretName: secretName,
+		},
+	}
+	return true
+}
+
+// getEndpointURL returns the URL of an endpoint exposed on host under basePath. The scheme is derived from the
+// endpoint's protocol attribute, using its TLS counterpart (https, wss) if tlsEnabled and its plain counterpart
+// otherwise; protocols other than http and ws are used as is. The endpoint's path attribute, if any, is appended
+// to basePath.
+func getEndpointURL(host, basePath string, attributes map[v1alpha1.EndpointAttribute]string, tlsEnabled bool) string {
+	scheme := attributes[v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE]
+	switch scheme {
+	case "", "http", "https":
+		scheme = "http"
+		if tlsEnabled {
+			scheme = "https"
+		}
+	case "ws", "wss":
+		scheme = "ws"
+		if tlsEnabled {
+			scheme = "wss"
+		}
+	}
+	path := strings.TrimSuffix(basePath, "/") + "/" + strings.TrimPrefix(attributes[v1alpha1.PATH_ENDPOINT_ATTRIBUTE], "/")
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
+
+// getInternalURL returns the URL of endpoint within the cluster, through the workspace's internal service. The
+// service's cluster DNS name is stable for the lifetime of the workspace.
+func getInternalURL(endpoint v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) string {
+	host := fmt.Sprintf("%s.%s.svc:%d", common.ServiceName(workspaceMeta.WorkspaceId), workspaceMeta.Namespace, endpoint.Port)
+	return getEndpointURL(host, "/", endpoint.Attributes, false)
+}
+
+// splitInternalEndpoints separates endpoints that are only exposed within the cluster from those that should be
+// exposed through an ingress or route
+func splitInternalEndpoints(endpoints map[string][]v1alpha1.Endpoint) (public, internal map[string][]v1alpha1.Endpoint) {
+	public = map[string][]v1alpha1.Endpoint{}
+	internal = map[string][]v1alpha1.Endpoint{}
+	for machineName, machineEndpoints := range endpoints {
+		for _, endpoint := range machineEndpoints {
+			if endpoint.Attributes[v1alpha1.INTERNAL_ENDPOINT_ATTRIBUTE] == "true" {
+				internal[machineName] = append(internal[machineName], endpoint)
+			} else {
+				public[machineName] = append(public[machineName], endpoint)
+			}
+		}
+	}
+	return public, internal
+}
+
+// addInternalExposedEndpoints adds the endpoints that are only exposed within the cluster to exposedEndpoints. Their
+// URL is their internal URL.
+func addInternalExposedEndpoints(
+	exposedEndpoints map[string][]v1alpha1.ExposedEndpoint,
+	internalEndpoints map[string][]v1alpha1.Endpoint,
+	workspaceMeta WorkspaceMetadata) {
+
+	for machineName, machineEndpoints := range internalEndpoints {
+		for _, endpoint := range machineEndpoints {
+			internalURL := getInternalURL(endpoint, workspaceMeta)
 			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], v1alpha1.ExposedEndpoint{
-				Name:       endpoint.Name,
-				Url:        ingressHostname,
-				Attributes: endpoint.Attributes,
+				Name:        endpoint.Name,
+				Url:         internalURL,
+				InternalUrl: internalURL,
+				Attributes:  endpoint.Attributes,
 			})
 		}
 	}
-	return ingresses, exposedEndpoints
+}
+
+// getEndpointIngressAnnotations returns the annotations for an endpoint's ingress: those configured for the routing
+// class, overridden by the endpoint's ingress annotation attributes. Attributes for annotations that are not in the
+// configured allowlist are ignored.
+func getEndpointIngressAnnotations(endpoint v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) (map[string]string, error) {
+	annotations, err := config.ControllerCfg.GetIngressAnnotations(string(workspaceMeta.RoutingClass))
+	if err != nil {
+		return nil, err
+	}
+	for attribute, value := range endpoint.Attributes {
+		if !strings.HasPrefix(string(attribute), v1alpha1.INGRESS_ANNOTATION_ENDPOINT_ATTRIBUTE_PREFIX) {
+			continue
+		}
+		annotation := strings.TrimPrefix(string(attribute), v1alpha1.INGRESS_ANNOTATION_ENDPOINT_ATTRIBUTE_PREFIX)
+		if !config.ControllerCfg.IsEndpointIngressAnnotationAllowed(annotation) {
+			continue
+		}
+		if value == "" {
+			delete(annotations, annotation)
+		} else {
+			annotations[annotation] = value
+		}
+	}
+	return annotations, nil
 }
@@ -0,0 +1,223 @@
+package solvers
+
+import (
+	"testing"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var testWorkspaceMeta = WorkspaceMetadata{
+	WorkspaceId:         "workspace123",
+	Namespace:           "test-ns",
+	IngressGlobalDomain: "apps.example.com",
+}
+
+func TestGetEndpointURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		protocol   string
+		secure     string
+		path       string
+		tlsEnabled bool
+		expected   string
+	}{
+		{name: "no protocol", expected: "http://host.example.com/base/"},
+		{name: "no protocol with TLS", tlsEnabled: true, expected: "https://host.example.com/base/"},
+		{name: "http", protocol: "http", expected: "http://host.example.com/base/"},
+		{name: "http with TLS", protocol: "http", tlsEnabled: true, expected: "https://host.example.com/base/"},
+		{name: "https without TLS", protocol: "https", expected: "http://host.example.com/base/"},
+		{name: "https with TLS", protocol: "https", tlsEnabled: true, expected: "https://host.example.com/base/"},
+		{name: "ws", protocol: "ws", expected: "ws://host.example.com/base/"},
+		{name: "ws with TLS", protocol: "ws", tlsEnabled: true, expected: "wss://host.example.com/base/"},
+		{name: "wss without TLS", protocol: "wss", expected: "ws://host.example.com/base/"},
+		{name: "wss with TLS", protocol: "wss", tlsEnabled: true, expected: "wss://host.example.com/base/"},
+		{name: "tcp", protocol: "tcp", expected: "tcp://host.example.com/base/"},
+		{name: "tcp with TLS", protocol: "tcp", tlsEnabled: true, expected: "tcp://host.example.com/base/"},
+		{name: "secure without TLS", secure: "true", expected: "http://host.example.com/base/"},
+		{name: "secure with TLS", secure: "true", tlsEnabled: true, expected: "https://host.example.com/base/"},
+		{name: "path with leading slash", path: "/api/v1", expected: "http://host.example.com/base/api/v1"},
+		{name: "path without leading slash", path: "api/v1", expected: "http://host.example.com/base/api/v1"},
+		{name: "path with trailing slash", path: "/api/", expected: "http://host.example.com/base/api/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := map[v1alpha1.EndpointAttribute]string{}
+			if tt.protocol != "" {
+				attributes[v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE] = tt.protocol
+			}
+			if tt.secure != "" {
+				attributes[v1alpha1.SECURE_ENDPOINT_ATTRIBUTE] = tt.secure
+			}
+			if tt.path != "" {
+				attributes[v1alpha1.PATH_ENDPOINT_ATTRIBUTE] = tt.path
+			}
+			for _, basePath := range []string{"/base", "/base/"} {
+				actual := getEndpointURL("host.example.com", basePath, attributes, tt.tlsEnabled)
+				if actual != tt.expected {
+					t.Errorf("getEndpointURL with base path %q: expected %q, got %q", basePath, tt.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestGetInternalURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint v1alpha1.Endpoint
+		expected string
+	}{
+		{
+			name:     "no protocol",
+			endpoint: v1alpha1.Endpoint{Name: "web", Port: 8080},
+			expected: "http://service-workspace123.test-ns.svc:8080/",
+		},
+		{
+			name: "https is not used within the cluster",
+			endpoint: v1alpha1.Endpoint{Name: "web", Port: 8443, Attributes: map[v1alpha1.EndpointAttribute]string{
+				v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE: "https",
+			}},
+			expected: "http://service-workspace123.test-ns.svc:8443/",
+		},
+		{
+			name: "wss is not used within the cluster",
+			endpoint: v1alpha1.Endpoint{Name: "socket", Port: 3000, Attributes: map[v1alpha1.EndpointAttribute]string{
+				v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE: "wss",
+			}},
+			expected: "ws://service-workspace123.test-ns.svc:3000/",
+		},
+		{
+			name: "tcp",
+			endpoint: v1alpha1.Endpoint{Name: "db", Port: 5432, Attributes: map[v1alpha1.EndpointAttribute]string{
+				v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE: "tcp",
+			}},
+			expected: "tcp://service-workspace123.test-ns.svc:5432/",
+		},
+		{
+			name: "path",
+			endpoint: v1alpha1.Endpoint{Name: "web", Port: 8080, Attributes: map[v1alpha1.EndpointAttribute]string{
+				v1alpha1.PATH_ENDPOINT_ATTRIBUTE: "/api",
+			}},
+			expected: "http://service-workspace123.test-ns.svc:8080/api",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := getInternalURL(tt.endpoint, testWorkspaceMeta)
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveExternalEndpointURLs(t *testing.T) {
+	tcpAttributes := map[v1alpha1.EndpointAttribute]string{v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE: "tcp"}
+	tests := []struct {
+		name        string
+		service     *corev1.Service
+		url         string
+		nodeAddress string
+		expected    string
+	}{
+		{
+			name: "load balancer with hostname",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: []corev1.ServicePort{{Port: 5432}}},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com", IP: "10.0.0.1"}},
+				}},
+			},
+			expected: "lb.example.com:5432",
+		},
+		{
+			name: "load balancer with IP",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: []corev1.ServicePort{{Port: 5432}}},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+				}},
+			},
+			expected: "10.0.0.1:5432",
+		},
+		{
+			name: "load balancer without address",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: []corev1.ServicePort{{Port: 5432}}},
+			},
+			expected: "",
+		},
+		{
+			name: "node port",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 5432, NodePort: 30123}}},
+			},
+			expected: "apps.example.com:30123",
+		},
+		{
+			name: "node port with node address",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 5432, NodePort: 30123}}},
+			},
+			nodeAddress: "192.168.1.10",
+			expected:    "192.168.1.10:30123",
+		},
+		{
+			name: "node port not allocated",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 5432}}},
+			},
+			expected: "",
+		},
+		{
+			name:     "service not created",
+			expected: "",
+		},
+		{
+			name: "endpoint with URL is not changed",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 5432, NodePort: 30123}}},
+			},
+			url:      "http://host.example.com/",
+			expected: "http://host.example.com/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := v1alpha1.Endpoint{Name: "db", Port: 5432, Attributes: tcpAttributes}
+			exposedEndpoints := map[string][]v1alpha1.ExposedEndpoint{
+				"machine": {{Name: endpoint.Name, Url: tt.url, Attributes: endpoint.Attributes}},
+			}
+			var clusterServices []corev1.Service
+			if tt.service != nil {
+				tt.service.Name = getExternalServiceName(endpoint, testWorkspaceMeta)
+				clusterServices = append(clusterServices, *tt.service)
+			}
+			workspaceMeta := testWorkspaceMeta
+			workspaceMeta.NodeAddress = tt.nodeAddress
+			ResolveExternalEndpointURLs(exposedEndpoints, clusterServices, workspaceMeta)
+			if actual := exposedEndpoints["machine"][0].Url; actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAddExternalExposedEndpoints(t *testing.T) {
+	endpoint := v1alpha1.Endpoint{Name: "db", Port: 5432, Attributes: map[v1alpha1.EndpointAttribute]string{
+		v1alpha1.PROTOCOL_ENDPOINT_ATTRIBUTE: "tcp",
+	}}
+	exposedEndpoints := map[string][]v1alpha1.ExposedEndpoint{}
+	addExternalExposedEndpoints(exposedEndpoints, map[string][]v1alpha1.Endpoint{"machine": {endpoint}}, testWorkspaceMeta)
+	if len(exposedEndpoints["machine"]) != 1 {
+		t.Fatalf("expected one exposed endpoint, got %d", len(exposedEndpoints["machine"]))
+	}
+	exposed := exposedEndpoints["machine"][0]
+	if exposed.Url != "" {
+		t.Errorf("expected URL to be resolved later, got %q", exposed.Url)
+	}
+	if expected := "tcp://service-workspace123.test-ns.svc:5432/"; exposed.InternalUrl != expected {
+		t.Errorf("expected internal URL %q, got %q", expected, exposed.InternalUrl)
+	}
+}
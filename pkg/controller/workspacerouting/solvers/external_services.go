@@ -0,0 +1,132 @@
+package solvers
+
+import (
+	"fmt"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/common"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// splitNonHTTPEndpoints separates tcp and udp endpoints, which are exposed through external services, from endpoints
+// that can be exposed through ingresses or routes
+func splitNonHTTPEndpoints(endpoints map[string][]v1alpha1.Endpoint) (http, nonHTTP map[string][]v1alpha1.Endpoint) {
+	http = map[string][]v1alpha1.Endpoint{}
+	nonHTTP = map[string][]v1alpha1.Endpoint{}
+	for machineName, machineEndpoints := range endpoints {
+		for _, endpoint := range machineEndpoints {
+			if common.IsHTTPEndpoint(endpoint) {
+				http[machineName] = append(http[machineName], endpoint)
+			} else {
+				nonHTTP[machineName] = append(nonHTTP[machineName], endpoint)
+			}
+		}
+	}
+	return http, nonHTTP
+}
+
+// getExternalServices returns a service of the configured external service type for each endpoint. A service is
+// created per endpoint, as load balancers may not support mixing protocols.
+func getExternalServices(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) ([]corev1.Service, error) {
+	serviceType := corev1.ServiceType(config.ControllerCfg.GetRoutingExternalServiceType())
+	if serviceType != corev1.ServiceTypeLoadBalancer && serviceType != corev1.ServiceTypeNodePort {
+		return nil, fmt.Errorf("unsupported external service type '%s'; expected %s or %s",
+			serviceType, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort)
+	}
+
+	var services []corev1.Service
+	for _, machineEndpoints := range endpoints {
+		for _, endpoint := range machineEndpoints {
+			service := getService(getExternalServiceName(endpoint, workspaceMeta), []corev1.ServicePort{
+				{
+					Name:       common.EndpointName(endpoint.Name),
+					Protocol:   common.EndpointProtocol(endpoint),
+					Port:       int32(endpoint.Port),
+					TargetPort: intstr.FromInt(int(endpoint.Port)),
+				},
+			}, workspaceMeta)
+			service.Spec.Type = serviceType
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// addExternalExposedEndpoints adds endpoints exposed through external services to exposedEndpoints. Their URLs are
+// only known once the cluster assigns an address to the service, and are filled in by ResolveExternalEndpointURLs.
+func addExternalExposedEndpoints(
+	exposedEndpoints map[string][]v1alpha1.ExposedEndpoint,
+	externalEndpoints map[string][]v1alpha1.Endpoint,
+	workspaceMeta WorkspaceMetadata) {
+
+	for machineName, machineEndpoints := range externalEndpoints {
+		for _, endpoint := range machineEndpoints {
+			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], v1alpha1.ExposedEndpoint{
+				Name:        endpoint.Name,
+				InternalUrl: getInternalURL(endpoint, workspaceMeta),
+				Attributes:  endpoint.Attributes,
+			})
+		}
+	}
+}
+
+// ResolveExternalEndpointURLs sets the URLs of endpoints exposed through external services to the host:port at which
+// the cluster exposes them. For LoadBalancer services, this is the load balancer's address; for NodePort services,
+// it is the configured node address, or the ingress global domain if none is set, and the allocated node port.
+// Endpoints whose service has not been assigned an address yet are left without a URL.
+func ResolveExternalEndpointURLs(
+	exposedEndpoints map[string][]v1alpha1.ExposedEndpoint,
+	clusterServices []corev1.Service,
+	workspaceMeta WorkspaceMetadata) {
+
+	servicesByName := map[string]corev1.Service{}
+	for _, service := range clusterServices {
+		servicesByName[service.Name] = service
+	}
+	for machineName, machineEndpoints := range exposedEndpoints {
+		for idx, exposedEndpoint := range machineEndpoints {
+			// Endpoints exposed through ingresses, routes or internally already have a URL
+			if exposedEndpoint.Url != "" {
+				continue
+			}
+			endpoint := v1alpha1.Endpoint{Name: exposedEndpoint.Name, Attributes: exposedEndpoint.Attributes}
+			service, ok := servicesByName[getExternalServiceName(endpoint, workspaceMeta)]
+			if !ok || len(service.Spec.Ports) == 0 {
+				continue
+			}
+			exposedEndpoints[machineName][idx].Url = getExternalServiceAddress(service, workspaceMeta)
+		}
+	}
+}
+
+func getExternalServiceAddress(service corev1.Service, workspaceMeta WorkspaceMetadata) string {
+	port := service.Spec.Ports[0]
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		if len(service.Status.LoadBalancer.Ingress) == 0 {
+			return ""
+		}
+		lbIngress := service.Status.LoadBalancer.Ingress[0]
+		host := lbIngress.Hostname
+		if host == "" {
+			host = lbIngress.IP
+		}
+		return fmt.Sprintf("%s:%d", host, port.Port)
+	case corev1.ServiceTypeNodePort:
+		if port.NodePort == 0 {
+			return ""
+		}
+		host := workspaceMeta.NodeAddress
+		if host == "" {
+			host = workspaceMeta.IngressGlobalDomain
+		}
+		return fmt.Sprintf("%s:%d", host, port.NodePort)
+	}
+	return ""
+}
+
+func getExternalServiceName(endpoint v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) string {
+	return fmt.Sprintf("%s-%s-external", workspaceMeta.WorkspaceId, common.EndpointName(endpoint.Name))
+}
@@ -0,0 +1,239 @@
+package solvers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/common"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	IstioGatewayGVK             = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "Gateway"}
+	IstioVirtualServiceGVK      = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}
+	IstioAuthorizationPolicyGVK = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1beta1", Kind: "AuthorizationPolicy"}
+)
+
+// IstioSolver exposes endpoints through an Istio ingress gateway. Each workspace gets a Gateway listening for the
+// hosts of its endpoints, and a VirtualService for each endpoint. Hosts are subdomains of the routing's ingress
+// global domain, which is expected to resolve to the mesh's ingress gateway.
+//
+// If an authorization claim is configured, an AuthorizationPolicy only allows requests to the ports exposed through
+// the gateway from the workspace creator and allowed users. Other ports, e.g. those of internal, discoverable and
+// tcp or udp endpoints, can still be reached from within the mesh.
+type IstioSolver struct{}
+
+var _ RoutingSolver = (*IstioSolver)(nil)
+
+func (s *IstioSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) (RoutingObjects, error) {
+	services := getServicesForEndpoints(spec.Endpoints, workspaceMeta)
+
+	gatewaySelector, err := config.ControllerCfg.GetIstioGatewaySelector()
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	gatewayName := workspaceMeta.WorkspaceId + "-gateway"
+
+	var istioObjects []unstructured.Unstructured
+	var hosts []interface{}
+	var secureHosts []interface{}
+	gatewayPorts := map[int64]bool{}
+	exposedEndpoints := map[string][]v1alpha1.ExposedEndpoint{}
+	tlsSecretName := config.ControllerCfg.GetIngressTLSSecretName()
+	publicEndpoints, internalEndpoints := splitInternalEndpoints(spec.Endpoints)
+	httpEndpoints, externalEndpoints := splitNonHTTPEndpoints(publicEndpoints)
+	for machineName, machineEndpoints := range httpEndpoints {
+		for _, endpoint := range machineEndpoints {
+			endpointName := common.EndpointName(endpoint.Name)
+			host := fmt.Sprintf("%s-%s-%s.%s",
+				workspaceMeta.WorkspaceId, endpointName, strconv.FormatInt(endpoint.Port, 10), workspaceMeta.IngressGlobalDomain)
+			istioObjects = append(istioObjects, getVirtualService(workspaceMeta, endpointName, host, gatewayName, endpoint.Port))
+			gatewayPorts[endpoint.Port] = true
+
+			tlsEnabled := endpoint.Attributes[v1alpha1.SECURE_ENDPOINT_ATTRIBUTE] == "true" && tlsSecretName != ""
+			if tlsEnabled {
+				secureHosts = append(secureHosts, host)
+			} else {
+				hosts = append(hosts, host)
+			}
+			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], v1alpha1.ExposedEndpoint{
+				Name:        endpoint.Name,
+				Url:         getEndpointURL(host, "/", endpoint.Attributes, tlsEnabled),
+				InternalUrl: getInternalURL(endpoint, workspaceMeta),
+				Attributes:  endpoint.Attributes,
+			})
+		}
+	}
+	addInternalExposedEndpoints(exposedEndpoints, internalEndpoints, workspaceMeta)
+	addExternalExposedEndpoints(exposedEndpoints, externalEndpoints, workspaceMeta)
+	externalServices, err := getExternalServices(externalEndpoints, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	services = append(services, externalServices...)
+
+	if len(hosts) > 0 || len(secureHosts) > 0 {
+		istioObjects = append(istioObjects, getGateway(workspaceMeta, gatewayName, gatewaySelector, hosts, secureHosts, tlsSecretName))
+	}
+	if claim := config.ControllerCfg.GetIstioAuthorizationClaim(); claim != "" && workspaceMeta.Creator != "" && len(gatewayPorts) > 0 {
+		istioObjects = append(istioObjects, getAuthorizationPolicy(workspaceMeta, claim, gatewayPorts))
+	}
+
+	return RoutingObjects{
+		Services:         services,
+		IstioObjects:     istioObjects,
+		ExposedEndpoints: exposedEndpoints,
+	}, nil
+}
+
+// getGateway returns a Gateway serving hosts over http and secureHosts over https, using the certificate in
+// tlsSecretName. As the gateway reads the certificate, the secret must exist in the gateway's namespace.
+func getGateway(
+	workspaceMeta WorkspaceMetadata,
+	name string,
+	selector map[string]string,
+	hosts, secureHosts []interface{},
+	tlsSecretName string) unstructured.Unstructured {
+
+	var servers []interface{}
+	if len(hosts) > 0 {
+		servers = append(servers, map[string]interface{}{
+			"hosts": hosts,
+			"port": map[string]interface{}{
+				"name":     "http",
+				"number":   int64(80),
+				"protocol": "HTTP",
+			},
+		})
+	}
+	if len(secureHosts) > 0 {
+		servers = append(servers, map[string]interface{}{
+			"hosts": secureHosts,
+			"port": map[string]interface{}{
+				"name":     "https",
+				"number":   int64(443),
+				"protocol": "HTTPS",
+			},
+			"tls": map[string]interface{}{
+				"mode":           "SIMPLE",
+				"credentialName": tlsSecretName,
+			},
+		})
+	}
+	gatewaySelector := map[string]interface{}{}
+	for key, value := range selector {
+		gatewaySelector[key] = value
+	}
+
+	gateway := newIstioObject(IstioGatewayGVK, name, workspaceMeta)
+	gateway.Object["spec"] = map[string]interface{}{
+		"selector": gatewaySelector,
+		"servers":  servers,
+	}
+	return gateway
+}
+
+func getVirtualService(workspaceMeta WorkspaceMetadata, endpointName, host, gatewayName string, port int64) unstructured.Unstructured {
+	virtualService := newIstioObject(IstioVirtualServiceGVK, fmt.Sprintf("%s-%s", workspaceMeta.WorkspaceId, endpointName), workspaceMeta)
+	virtualService.Object["spec"] = map[string]interface{}{
+		"hosts":    []interface{}{host},
+		"gateways": []interface{}{gatewayName},
+		"http": []interface{}{
+			map[string]interface{}{
+				"route": []interface{}{
+					map[string]interface{}{
+						"destination": map[string]interface{}{
+							"host": common.ServiceName(workspaceMeta.WorkspaceId),
+							"port": map[string]interface{}{
+								"number": port,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return virtualService
+}
+
+// getAuthorizationPolicy returns a policy that only allows requests to gatewayPorts on the workspace's pods if the
+// username in the request's JWT claim is the workspace's creator or one of its allowed users. Requests to other ports
+// are allowed, as they are not exposed through the gateway and in-mesh clients do not have the user's JWT.
+func getAuthorizationPolicy(workspaceMeta WorkspaceMetadata, claim string, gatewayPorts map[int64]bool) unstructured.Unstructured {
+	users := map[string]bool{workspaceMeta.Creator: true}
+	for _, user := range workspaceMeta.AllowedUsers {
+		users[user] = true
+	}
+	var usernames []string
+	for user := range users {
+		usernames = append(usernames, user)
+	}
+	sort.Strings(usernames)
+	var values []interface{}
+	for _, user := range usernames {
+		values = append(values, user)
+	}
+	podSelector := map[string]interface{}{}
+	for key, value := range workspaceMeta.PodSelector {
+		podSelector[key] = value
+	}
+	var sortedPorts []int
+	for port := range gatewayPorts {
+		sortedPorts = append(sortedPorts, int(port))
+	}
+	sort.Ints(sortedPorts)
+	var ports []interface{}
+	for _, port := range sortedPorts {
+		ports = append(ports, strconv.Itoa(port))
+	}
+
+	policy := newIstioObject(IstioAuthorizationPolicyGVK, workspaceMeta.WorkspaceId+"-access", workspaceMeta)
+	policy.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": podSelector,
+		},
+		"action": "ALLOW",
+		"rules": []interface{}{
+			map[string]interface{}{
+				"to": []interface{}{
+					map[string]interface{}{
+						"operation": map[string]interface{}{
+							"ports": ports,
+						},
+					},
+				},
+				"when": []interface{}{
+					map[string]interface{}{
+						"key":    fmt.Sprintf("request.auth.claims[%s]", claim),
+						"values": values,
+					},
+				},
+			},
+			map[string]interface{}{
+				"to": []interface{}{
+					map[string]interface{}{
+						"operation": map[string]interface{}{
+							"notPorts": ports,
+						},
+					},
+				},
+			},
+		},
+	}
+	return policy
+}
+
+func newIstioObject(gvk schema.GroupVersionKind, name string, workspaceMeta WorkspaceMetadata) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(workspaceMeta.Namespace)
+	obj.SetLabels(map[string]string{
+		"app": workspaceMeta.WorkspaceId,
+	})
+	return obj
+}
@@ -1,28 +1,60 @@
 package solvers
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 	"github.com/che-incubator/che-workspace-operator/pkg/common"
 	"github.com/che-incubator/che-workspace-operator/pkg/config"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"path"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 const proxyServiceAcctAnnotationKeyFmt string = "serviceaccounts.openshift.io/oauth-redirectreference.%s-%s"
 const proxyServiceAcctAnnotationValueFmt string = `{"kind":"OAuthRedirectReference","apiVersion":"v1","reference":{"kind":"Route","name":"%s"}}`
 
-var openShiftProxySARFmt = `{"namespace": "%s", "resource": "pods", "name": "%s", "verb": "exec"}`
+const (
+	proxyCookieSecretKey    = "cookie_secret"
+	proxyCookieSecretEnvVar = "COOKIE_SECRET"
+	// oauth-proxy accepts cookie secrets of 16, 24, or 32 bytes; 24 random bytes are base64-encoded to 32 characters
+	proxyCookieSecretBytes = 24
+)
+
+const (
+	proxyAccessVolumeName      = "proxy-access"
+	proxyAccessMountPath       = "/etc/proxy-access"
+	proxyAuthenticatedUsersKey = "authenticated-emails"
+	proxyPodNameEnvVar         = "POD_NAME"
+	// The openshift provider of oauth-proxy identifies users by an email address of this form
+	proxyUserEmailFmt = "%s@cluster.local"
+)
+
+// openShiftProxySARFmt is a SubjectAccessReview that only admits users allowed to exec into the given pod
+var openShiftProxySARFmt = `{"namespace": "%s", "resource": "pods", "subresource": "exec", "name": "%s", "verb": "create"}`
 
 func getProxyPodAdditions(proxyEndpoints map[string]proxyEndpoint, meta WorkspaceMetadata) *v1alpha1.PodAdditions {
 	var proxyContainers []corev1.Container
 	for _, proxyEndpoint := range proxyEndpoints {
 		proxyContainers = append(proxyContainers, getProxyContainerForEndpoint(proxyEndpoint, meta))
 	}
-	proxyVolumes := getProxyVolumes(proxyContainers)
+	proxyVolumes := getProxyVolumes(proxyContainers, meta)
 	serviceAcctAnnotations := getProxyServiceAcctAnnotations(proxyEndpoints, meta)
 
+	// Proxies only read the cookie secret on startup, so the pod has to be restarted when it is rotated
+	var podAnnotations map[string]string
+	if meta.CookieSecretRotation != "" {
+		podAnnotations = map[string]string{
+			config.WorkspaceCookieSecretRotationAnnotation: meta.CookieSecretRotation,
+		}
+	}
+
 	return &v1alpha1.PodAdditions{
+		Annotations:               podAnnotations,
 		Containers:                proxyContainers,
 		Volumes:                   proxyVolumes,
 		ServiceAccountAnnotations: serviceAcctAnnotations,
@@ -32,7 +64,7 @@ func getProxyPodAdditions(proxyEndpoints map[string]proxyEndpoint, meta Workspac
 func getProxyContainerForEndpoint(proxyEndpoint proxyEndpoint, meta WorkspaceMetadata) corev1.Container {
 	proxyContainerName := fmt.Sprintf("%s-oauth-proxy-%s", meta.WorkspaceId, strconv.FormatInt(proxyEndpoint.upstreamEndpoint.Port, 10))
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name: proxyContainerName,
 		Ports: []corev1.ContainerPort{
 			{
@@ -50,6 +82,27 @@ func getProxyContainerForEndpoint(proxyEndpoint proxyEndpoint, meta WorkspaceMet
 		},
 		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		Image:                    "openshift/oauth-proxy:latest",
+		Env: []corev1.EnvVar{
+			{
+				Name: proxyCookieSecretEnvVar,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: getProxyCookieSecretName(meta.WorkspaceId),
+						},
+						Key: proxyCookieSecretKey,
+					},
+				},
+			},
+			{
+				Name: proxyPodNameEnvVar,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			},
+		},
 		Args: []string{
 			"--https-address=:" + strconv.FormatInt(proxyEndpoint.publicEndpoint.Port, 10),
 			"--http-address=127.0.0.1:" + strconv.FormatInt(proxyEndpoint.publicEndpointHttpPort, 10),
@@ -59,14 +112,27 @@ func getProxyContainerForEndpoint(proxyEndpoint proxyEndpoint, meta WorkspaceMet
 			"--upstream=http://localhost:" + strconv.FormatInt(proxyEndpoint.upstreamEndpoint.Port, 10),
 			"--tls-cert=/etc/tls/private/tls.crt",
 			"--tls-key=/etc/tls/private/tls.key",
-			"--cookie-secret=SECRET",
-			// Currently: block anyone who can't exec in the current namespace
-			"--openshift-sar=" + fmt.Sprintf(openShiftProxySARFmt, "", ""),
+			"--cookie-secret=$(" + proxyCookieSecretEnvVar + ")",
+			// Block anyone who can't exec into the workspace pod
+			"--openshift-sar=" + fmt.Sprintf(openShiftProxySARFmt, meta.Namespace, "$("+proxyPodNameEnvVar+")"),
 		},
 	}
+
+	// If the creator is known, additionally restrict access to the creator and the users they allowed
+	if meta.Creator != "" {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      proxyAccessVolumeName,
+			MountPath: proxyAccessMountPath,
+			ReadOnly:  true,
+		})
+		container.Args = append(container.Args,
+			"--authenticated-emails-file="+path.Join(proxyAccessMountPath, proxyAuthenticatedUsersKey))
+	}
+
+	return container
 }
 
-func getProxyVolumes(containers []corev1.Container) []corev1.Volume {
+func getProxyVolumes(containers []corev1.Container, meta WorkspaceMetadata) []corev1.Volume {
 	var volumes []corev1.Volume
 	volumeNames := map[string]bool{}
 	var volumeDefaultMode int32 = 420
@@ -84,6 +150,16 @@ func getProxyVolumes(containers []corev1.Container) []corev1.Volume {
 					},
 				},
 			}
+			if volumeMount.Name == proxyAccessVolumeName {
+				volume.VolumeSource = corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: getProxyAccessConfigMapName(meta.WorkspaceId),
+						},
+						DefaultMode: &volumeDefaultMode,
+					},
+				}
+			}
 			volumes = append(volumes, volume)
 			volumeNames[volumeMount.Name] = true
 		}
@@ -105,3 +181,68 @@ func getProxyServiceAcctAnnotations(proxyEndpoints map[string]proxyEndpoint, met
 
 	return annotations
 }
+
+// getProxyCookieSecret returns a Secret containing a randomly generated cookie secret shared by the workspace's
+// oauth proxies. The Secret records the rotation value it was generated for, so that it is only regenerated when
+// rotation is requested.
+func getProxyCookieSecret(meta WorkspaceMetadata) (*corev1.Secret, error) {
+	cookieSecret := make([]byte, proxyCookieSecretBytes)
+	_, err := rand.Read(cookieSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate oauth proxy cookie secret: %w", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getProxyCookieSecretName(meta.WorkspaceId),
+			Namespace: meta.Namespace,
+			Labels: map[string]string{
+				"app": meta.WorkspaceId,
+			},
+			Annotations: map[string]string{
+				config.WorkspaceCookieSecretRotationAnnotation: meta.CookieSecretRotation,
+			},
+		},
+		Data: map[string][]byte{
+			proxyCookieSecretKey: []byte(base64.StdEncoding.EncodeToString(cookieSecret)),
+		},
+		Type: corev1.SecretTypeOpaque,
+	}, nil
+}
+
+func getProxyCookieSecretName(workspaceId string) string {
+	return workspaceId + "-oauth-proxy-cookie"
+}
+
+// getProxyAccessConfigMap returns a ConfigMap listing the users allowed through the workspace's oauth proxies: the
+// workspace creator and any additionally allowed users. The proxies watch the file for changes, so updating the list
+// does not require restarting the workspace.
+func getProxyAccessConfigMap(meta WorkspaceMetadata) *corev1.ConfigMap {
+	users := map[string]bool{meta.Creator: true}
+	for _, user := range meta.AllowedUsers {
+		users[user] = true
+	}
+	var emails []string
+	for user := range users {
+		emails = append(emails, fmt.Sprintf(proxyUserEmailFmt, user))
+	}
+	// Sort to avoid needless updates to the ConfigMap
+	sort.Strings(emails)
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getProxyAccessConfigMapName(meta.WorkspaceId),
+			Namespace: meta.Namespace,
+			Labels: map[string]string{
+				"app": meta.WorkspaceId,
+			},
+		},
+		Data: map[string]string{
+			proxyAuthenticatedUsersKey: strings.Join(emails, "\n") + "\n",
+		},
+	}
+}
+
+func getProxyAccessConfigMapName(workspaceId string) string {
+	return workspaceId + "-oauth-proxy-access"
+}
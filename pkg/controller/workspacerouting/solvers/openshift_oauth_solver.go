@@ -5,6 +5,7 @@ import (
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
 	"github.com/che-incubator/che-workspace-operator/pkg/common"
 	routeV1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"strconv"
@@ -21,10 +22,15 @@ type proxyEndpoint struct {
 	publicEndpointHttpPort int64
 }
 
-func (s *OpenShiftOAuthSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) RoutingObjects {
+func (s *OpenShiftOAuthSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) (RoutingObjects, error) {
 	var exposedEndpoints = map[string][]v1alpha1.ExposedEndpoint{}
-	proxy, noProxy := getProxiedEndpoints(spec)
-	defaultIngresses, defaultEndpoints := getIngressesForSpec(noProxy, workspaceMeta)
+	publicEndpoints, internalEndpoints := splitInternalEndpoints(spec.Endpoints)
+	httpEndpoints, externalEndpoints := splitNonHTTPEndpoints(publicEndpoints)
+	proxy, noProxy := getProxiedEndpoints(httpEndpoints)
+	defaultIngresses, defaultEndpoints, err := getIngressesForSpec(noProxy, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
 	for machineName, machineEndpoints := range defaultEndpoints {
 		exposedEndpoints[machineName] = append(exposedEndpoints[machineName], machineEndpoints...)
 	}
@@ -37,26 +43,60 @@ func (s *OpenShiftOAuthSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec
 	for machineName, machineEndpoints := range noProxy {
 		proxyPorts[machineName] = append(proxyPorts[machineName], machineEndpoints...)
 	}
+	// Internal endpoints are not proxied, as they are only reachable from within the cluster; tcp and udp endpoints
+	// cannot be proxied
+	for machineName, machineEndpoints := range internalEndpoints {
+		proxyPorts[machineName] = append(proxyPorts[machineName], machineEndpoints...)
+	}
+	for machineName, machineEndpoints := range externalEndpoints {
+		proxyPorts[machineName] = append(proxyPorts[machineName], machineEndpoints...)
+	}
+	addInternalExposedEndpoints(exposedEndpoints, internalEndpoints, workspaceMeta)
+	addExternalExposedEndpoints(exposedEndpoints, externalEndpoints, workspaceMeta)
 	// Use common service for all unproxied endpoints
 	proxyServices := getServicesForEndpoints(proxyPorts, workspaceMeta)
 	for idx := range proxyServices {
+		// Only the common service serves the proxies; services for discoverable endpoints share its pods
+		if proxyServices[idx].Name != common.ServiceName(workspaceMeta.WorkspaceId) {
+			continue
+		}
 		proxyServices[idx].Annotations = map[string]string{
 			"service.alpha.openshift.io/serving-cert-secret-name": "proxy-tls", // TODO: Find a better way to do this
 		}
 	}
+	externalServices, err := getExternalServices(externalEndpoints, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	proxyServices = append(proxyServices, externalServices...)
 
 	routes, proxyEndpoints, podAdditions := s.getProxyRoutes(proxy, workspaceMeta, portMappings)
 	for machineName, machineEndpoints := range proxyEndpoints {
 		exposedEndpoints[machineName] = append(exposedEndpoints[machineName], machineEndpoints...)
 	}
 
+	var secrets []corev1.Secret
+	var configMaps []corev1.ConfigMap
+	if len(portMappings) > 0 {
+		cookieSecret, err := getProxyCookieSecret(workspaceMeta)
+		if err != nil {
+			return RoutingObjects{}, err
+		}
+		secrets = append(secrets, *cookieSecret)
+		if workspaceMeta.Creator != "" {
+			configMaps = append(configMaps, *getProxyAccessConfigMap(workspaceMeta))
+		}
+	}
+
 	return RoutingObjects{
 		Services:         proxyServices,
+		Secrets:          secrets,
+		ConfigMaps:       configMaps,
 		Ingresses:        defaultIngresses,
 		Routes:           routes,
 		PodAdditions:     podAdditions,
 		ExposedEndpoints: exposedEndpoints,
-	}
+	}, nil
 }
 
 func (s *OpenShiftOAuthSolver) getProxyRoutes(
@@ -105,7 +145,7 @@ func (s *OpenShiftOAuthSolver) getProxyRoutes(
 					Host: hostname,
 					To: routeV1.RouteTargetReference{
 						Kind: "Service",
-						Name: "service-" + workspaceMeta.WorkspaceId, // TODO: Copied from service func above
+						Name: common.ServiceName(workspaceMeta.WorkspaceId),
 					},
 					Port: &routeV1.RoutePort{
 						TargetPort: targetEndpoint,
@@ -115,7 +155,7 @@ func (s *OpenShiftOAuthSolver) getProxyRoutes(
 			})
 			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], v1alpha1.ExposedEndpoint{
 				Name:       endpoint.Name,
-				Url:        hostname,
+				Url:        getEndpointURL(hostname, "/", endpoint.Attributes, tls != nil),
 				Attributes: endpoint.Attributes,
 			})
 		}
@@ -126,10 +166,10 @@ func (s *OpenShiftOAuthSolver) getProxyRoutes(
 
 
 
-func getProxiedEndpoints(spec v1alpha1.WorkspaceRoutingSpec) (proxy, noProxy map[string][]v1alpha1.Endpoint) {
+func getProxiedEndpoints(endpoints map[string][]v1alpha1.Endpoint) (proxy, noProxy map[string][]v1alpha1.Endpoint) {
 	proxy = map[string][]v1alpha1.Endpoint{}
 	noProxy = map[string][]v1alpha1.Endpoint{}
-	for machineName, machineEndpoints := range spec.Endpoints {
+	for machineName, machineEndpoints := range endpoints {
 		for _, endpoint := range machineEndpoints {
 			// TODO: Meaning of v1alpha1.PUBLIC_ENDPOINT_ATTRIBUTE = true is unclear
 			if endpointNeedsProxy(endpoint) {
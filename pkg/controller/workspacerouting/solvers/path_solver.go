@@ -0,0 +1,100 @@
+package solvers
+
+import (
+	"fmt"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/common"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PathSolver exposes all endpoints on a single host, the routing's ingress global domain, with each endpoint served
+// under the path /<workspaceId>/<endpoint>/. The path prefix, which is matched by the first two capture groups of
+// the ingress path, is stripped before requests are passed to the endpoint. By default, this relies on the rewrite
+// annotations of the nginx ingress controller; other ingress controllers require setting ingress.annotations.path.
+type PathSolver struct{}
+
+var _ RoutingSolver = (*PathSolver)(nil)
+
+func (s *PathSolver) GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) (RoutingObjects, error) {
+	services := getServicesForEndpoints(spec.Endpoints, workspaceMeta)
+	publicEndpoints, internalEndpoints := splitInternalEndpoints(spec.Endpoints)
+	httpEndpoints, externalEndpoints := splitNonHTTPEndpoints(publicEndpoints)
+	ingresses, exposedEndpoints, err := getPathIngressesForSpec(httpEndpoints, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	externalServices, err := getExternalServices(externalEndpoints, workspaceMeta)
+	if err != nil {
+		return RoutingObjects{}, err
+	}
+	services = append(services, externalServices...)
+	addInternalExposedEndpoints(exposedEndpoints, internalEndpoints, workspaceMeta)
+	addExternalExposedEndpoints(exposedEndpoints, externalEndpoints, workspaceMeta)
+
+	return RoutingObjects{
+		Services:         services,
+		Ingresses:        ingresses,
+		ExposedEndpoints: exposedEndpoints,
+	}, nil
+}
+
+// getPathIngressesForSpec returns an ingress for each endpoint, all using the same host
+func getPathIngressesForSpec(endpoints map[string][]v1alpha1.Endpoint, workspaceMeta WorkspaceMetadata) ([]v1beta1.Ingress, map[string][]v1alpha1.ExposedEndpoint, error) {
+	var ingresses []v1beta1.Ingress
+	exposedEndpoints := map[string][]v1alpha1.ExposedEndpoint{}
+	host := workspaceMeta.IngressGlobalDomain
+
+	for machineName, machineEndpoints := range endpoints {
+		for _, endpoint := range machineEndpoints {
+			ingressAnnotations, err := getEndpointIngressAnnotations(endpoint, workspaceMeta)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			endpointName := common.EndpointName(endpoint.Name)
+			endpointPath := fmt.Sprintf("/%s/%s/", workspaceMeta.WorkspaceId, endpointName)
+			ingress := v1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%s", workspaceMeta.WorkspaceId, endpointName),
+					Namespace: workspaceMeta.Namespace,
+					Labels: map[string]string{
+						"app": workspaceMeta.WorkspaceId,
+					},
+					Annotations: ingressAnnotations,
+				},
+				Spec: v1beta1.IngressSpec{
+					Rules: []v1beta1.IngressRule{
+						{
+							Host: host,
+							IngressRuleValue: v1beta1.IngressRuleValue{
+								HTTP: &v1beta1.HTTPIngressRuleValue{
+									Paths: []v1beta1.HTTPIngressPath{
+										{
+											Path: fmt.Sprintf("/%s/%s(/|$)(.*)", workspaceMeta.WorkspaceId, endpointName),
+											Backend: v1beta1.IngressBackend{
+												ServiceName: common.ServiceName(workspaceMeta.WorkspaceId),
+												ServicePort: intstr.FromInt(int(endpoint.Port)),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			tlsEnabled := endpoint.Attributes[v1alpha1.SECURE_ENDPOINT_ATTRIBUTE] == "true" && addIngressTLS(&ingress, host)
+			ingresses = append(ingresses, ingress)
+			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], v1alpha1.ExposedEndpoint{
+				Name:        endpoint.Name,
+				Url:         getEndpointURL(host, endpointPath, endpoint.Attributes, tlsEnabled),
+				InternalUrl: getInternalURL(endpoint, workspaceMeta),
+				Attributes:  endpoint.Attributes,
+			})
+		}
+	}
+	return ingresses, exposedEndpoints, nil
+}
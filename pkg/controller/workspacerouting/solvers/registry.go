@@ -0,0 +1,54 @@
+package solvers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+)
+
+// RoutingSolverFactory returns a RoutingSolver used to reconcile a single WorkspaceRouting.
+type RoutingSolverFactory func() RoutingSolver
+
+var (
+	solverFactoriesMu sync.RWMutex
+	solverFactories   = map[v1alpha1.WorkspaceRoutingClass]RoutingSolverFactory{
+		v1alpha1.WorkspaceRoutingDefault:        func() RoutingSolver { return &BasicSolver{} },
+		v1alpha1.WorkspaceRoutingOpenShiftOauth: func() RoutingSolver { return &OpenShiftOAuthSolver{} },
+		v1alpha1.WorkspaceRoutingPath:           func() RoutingSolver { return &PathSolver{} },
+		v1alpha1.WorkspaceRoutingIstio:          func() RoutingSolver { return &IstioSolver{} },
+	}
+)
+
+// RegisterSolver makes a solver available for WorkspaceRoutings with the given routing class. It is intended to be
+// called from the main package of custom builds of the operator, before the manager is started. Returns an error if
+// a solver is already registered for routingClass.
+func RegisterSolver(routingClass v1alpha1.WorkspaceRoutingClass, factory RoutingSolverFactory) error {
+	solverFactoriesMu.Lock()
+	defer solverFactoriesMu.Unlock()
+	if _, exists := solverFactories[routingClass]; exists {
+		return fmt.Errorf("solver for routing class '%s' is already registered", routingClass)
+	}
+	solverFactories[routingClass] = factory
+	return nil
+}
+
+// GetSolver returns a solver for routingClass. Returns false if no solver is registered for it, in which case the
+// WorkspaceRouting is expected to be reconciled by an external controller.
+func GetSolver(routingClass v1alpha1.WorkspaceRoutingClass) (solver RoutingSolver, ok bool) {
+	solverFactoriesMu.RLock()
+	defer solverFactoriesMu.RUnlock()
+	factory, ok := solverFactories[routingClass]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// IsRegistered returns true if a solver is registered for routingClass.
+func IsRegistered(routingClass v1alpha1.WorkspaceRoutingClass) bool {
+	solverFactoriesMu.RLock()
+	defer solverFactoriesMu.RUnlock()
+	_, ok := solverFactories[routingClass]
+	return ok
+}
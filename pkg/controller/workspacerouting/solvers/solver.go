@@ -5,17 +5,33 @@ import (
 	v12 "github.com/openshift/api/route/v1"
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// RoutingObjects are the objects required to expose a workspace's endpoints. The routing controller creates them on
+// the cluster, owned by the WorkspaceRouting, and removes objects it created previously that are no longer returned.
+// IstioObjects are unstructured, as the operator does not depend on Istio's client libraries.
 type RoutingObjects struct {
 	Services         []v1.Service
+	Secrets          []v1.Secret
+	ConfigMaps       []v1.ConfigMap
 	Ingresses        []v1beta1.Ingress
 	Routes           []v12.Route
+	IstioObjects     []unstructured.Unstructured
 	PodAdditions     *v1alpha1.PodAdditions
 	ExposedEndpoints map[string][]v1alpha1.ExposedEndpoint
 }
 
+// RoutingSolver computes the objects required to expose a workspace's endpoints for a routing class. Solvers are
+// registered with RegisterSolver; the returned objects are synced to the cluster by the routing controller, and the
+// PodAdditions and ExposedEndpoints are reported in the WorkspaceRouting's status.
+//
+// WorkspaceRoutings with a routing class that has no registered solver are ignored by the routing controller. An
+// external controller may reconcile them instead; it must fill in Status.PodAdditions and Status.ExposedEndpoints
+// and set Status.Ready once the endpoints are exposed, as the workspace does not start until then.
 type RoutingSolver interface {
-	GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) RoutingObjects
+	// GetSpecObjects returns the objects required to expose spec's endpoints. It is called on every reconcile and
+	// should return the same objects for the same input.
+	GetSpecObjects(spec v1alpha1.WorkspaceRoutingSpec, workspaceMeta WorkspaceMetadata) (RoutingObjects, error)
 }
 
@@ -0,0 +1,97 @@
+package workspacerouting
+
+import (
+	"context"
+	"fmt"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncConfigMaps creates, updates, and removes configmaps so that the configmaps owned by the routing match the spec.
+func (r *ReconcileWorkspaceRouting) syncConfigMaps(routing *v1alpha1.WorkspaceRouting, specConfigMaps []corev1.ConfigMap) (ok bool, err error) {
+	configMapsInSync := true
+
+	clusterConfigMaps, err := r.getClusterConfigMaps(routing)
+	if err != nil {
+		return false, err
+	}
+
+	toDelete := getConfigMapsToDelete(clusterConfigMaps, specConfigMaps)
+	for _, configMap := range toDelete {
+		err := r.client.Delete(context.TODO(), &configMap)
+		if err != nil {
+			return false, err
+		}
+		configMapsInSync = false
+	}
+
+	for _, specConfigMap := range specConfigMaps {
+		if contains, idx := listContainsConfigMapByName(specConfigMap, clusterConfigMaps); contains {
+			clusterConfigMap := clusterConfigMaps[idx]
+			if !cmp.Equal(specConfigMap.Data, clusterConfigMap.Data) {
+				log.Info("Updating configmap", "name", clusterConfigMap.Name)
+				clusterConfigMap.Data = specConfigMap.Data
+				err := r.client.Update(context.TODO(), &clusterConfigMap)
+				if err != nil {
+					return false, err
+				}
+				configMapsInSync = false
+			}
+		} else {
+			err := r.client.Create(context.TODO(), &specConfigMap)
+			if err != nil {
+				return false, err
+			}
+			configMapsInSync = false
+		}
+	}
+
+	return configMapsInSync, nil
+}
+
+func (r *ReconcileWorkspaceRouting) getClusterConfigMaps(routing *v1alpha1.WorkspaceRouting) ([]corev1.ConfigMap, error) {
+	found := &corev1.ConfigMapList{}
+	labelSelector, err := labels.Parse(fmt.Sprintf("app=%s", routing.Spec.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	listOptions := &client.ListOptions{
+		Namespace:     routing.Namespace,
+		LabelSelector: labelSelector,
+	}
+	err = r.client.List(context.TODO(), found, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	// ConfigMaps from e.g. kubernetes component recipes may share labels with workspace configmaps; only consider our own
+	var configMaps []corev1.ConfigMap
+	for _, configMap := range found.Items {
+		if metav1.IsControlledBy(&configMap, routing) {
+			configMaps = append(configMaps, configMap)
+		}
+	}
+	return configMaps, nil
+}
+
+func getConfigMapsToDelete(clusterConfigMaps, specConfigMaps []corev1.ConfigMap) []corev1.ConfigMap {
+	var toDelete []corev1.ConfigMap
+	for _, clusterConfigMap := range clusterConfigMaps {
+		if contains, _ := listContainsConfigMapByName(clusterConfigMap, specConfigMaps); !contains {
+			toDelete = append(toDelete, clusterConfigMap)
+		}
+	}
+	return toDelete
+}
+
+func listContainsConfigMapByName(query corev1.ConfigMap, list []corev1.ConfigMap) (exists bool, idx int) {
+	for idx, listConfigMap := range list {
+		if query.Name == listConfigMap.Name {
+			return true, idx
+		}
+	}
+	return false, -1
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/api/extensions/v1beta1"
@@ -15,9 +16,19 @@ var ingressDiffOpts = cmp.Options{
 	cmpopts.IgnoreFields(v1beta1.Ingress{}, "TypeMeta", "ObjectMeta", "Status"),
 }
 
+// ingressClassAnnotation sets the ingress class on extensions/v1beta1 ingresses, which have no ingressClassName field
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
 func (r *ReconcileWorkspaceRouting) syncIngresses(routing *v1alpha1.WorkspaceRouting, specIngresses []v1beta1.Ingress) (ok bool, err error) {
 	ingressesInSync := true
 
+	for idx := range specIngresses {
+		if specIngresses[idx].Annotations == nil {
+			specIngresses[idx].Annotations = map[string]string{}
+		}
+		specIngresses[idx].Annotations[ingressClassAnnotation] = config.ControllerCfg.GetIngressClass(string(routing.Spec.RoutingClass))
+	}
+
 	clusterIngresses, err := r.getClusterIngresses(routing)
 	if err != nil {
 		return false, err
@@ -35,9 +46,10 @@ func (r *ReconcileWorkspaceRouting) syncIngresses(routing *v1alpha1.WorkspaceRou
 	for _, specIngress := range specIngresses {
 		if contains, idx := listContainsIngressByName(specIngress, clusterIngresses); contains {
 			clusterIngress := clusterIngresses[idx]
-			if !cmp.Equal(specIngress, clusterIngress, ingressDiffOpts) {
-				fmt.Printf("\n\n%s\n\n", cmp.Diff(specIngress, clusterIngress, ingressDiffOpts))
-				// Update ingress's spec
+			if !cmp.Equal(specIngress, clusterIngress, ingressDiffOpts) || !annotationsInSync(specIngress.Annotations, clusterIngress.Annotations) {
+				log.V(1).Info("Updating ingress", "name", clusterIngress.Name, "diff", cmp.Diff(specIngress, clusterIngress, ingressDiffOpts))
+				// Update ingress's spec and annotations
+				clusterIngress.Annotations = mergeAnnotations(specIngress.Annotations, clusterIngress.Annotations)
 				clusterIngress.Spec = specIngress.Spec
 				err := r.client.Update(context.TODO(), &clusterIngress)
 				if err != nil {
@@ -57,6 +69,30 @@ func (r *ReconcileWorkspaceRouting) syncIngresses(routing *v1alpha1.WorkspaceRou
 	return ingressesInSync, nil
 }
 
+// annotationsInSync returns true if every annotation in spec has the same value in cluster. Other annotations on the
+// cluster object, e.g. ones added by ingress controllers, are ignored.
+func annotationsInSync(spec, cluster map[string]string) bool {
+	for key, value := range spec {
+		if clusterValue, ok := cluster[key]; !ok || clusterValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeAnnotations returns the cluster object's annotations with the annotations in spec set. Other annotations are
+// kept as they are.
+func mergeAnnotations(spec, cluster map[string]string) map[string]string {
+	merged := map[string]string{}
+	for key, value := range cluster {
+		merged[key] = value
+	}
+	for key, value := range spec {
+		merged[key] = value
+	}
+	return merged
+}
+
 func (r *ReconcileWorkspaceRouting) getClusterIngresses(routing *v1alpha1.WorkspaceRouting) ([]v1beta1.Ingress, error) {
 	found := &v1beta1.IngressList{}
 	labelSelector, err := labels.Parse(fmt.Sprintf("app=%s", routing.Spec.WorkspaceId)) // TODO This is manually synced with what's created, that's bad.
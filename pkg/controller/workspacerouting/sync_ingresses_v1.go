@@ -0,0 +1,211 @@
+package workspacerouting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The client libraries used by the operator predate networking.k8s.io/v1 Ingresses, so they are handled as
+// unstructured objects.
+var ingressV1GVK = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+
+const (
+	pathTypePrefix                 = "Prefix"
+	pathTypeImplementationSpecific = "ImplementationSpecific"
+)
+
+// syncIngressesV1 converts the spec ingresses to networking.k8s.io/v1 Ingresses and syncs them to the cluster.
+func (r *ReconcileWorkspaceRouting) syncIngressesV1(routing *v1alpha1.WorkspaceRouting, specIngresses []v1beta1.Ingress) (ok bool, err error) {
+	ingressesInSync := true
+
+	ingressClass := config.ControllerCfg.GetIngressClass(string(routing.Spec.RoutingClass))
+	var specIngressesV1 []*unstructured.Unstructured
+	for _, specIngress := range specIngresses {
+		specIngressesV1 = append(specIngressesV1, convertIngressToV1(specIngress, ingressClass))
+	}
+
+	clusterIngresses, err := r.getClusterIngressesV1(routing)
+	if err != nil {
+		return false, err
+	}
+
+	toDelete := getIngressesV1ToDelete(clusterIngresses, specIngressesV1)
+	for _, ingress := range toDelete {
+		err := r.client.Delete(context.TODO(), ingress)
+		if err != nil {
+			return false, err
+		}
+		ingressesInSync = false
+	}
+
+	for _, specIngress := range specIngressesV1 {
+		if contains, idx := listContainsIngressV1ByName(specIngress, clusterIngresses); contains {
+			clusterIngress := clusterIngresses[idx]
+			if !equality.Semantic.DeepEqual(specIngress.Object["spec"], clusterIngress.Object["spec"]) ||
+				!annotationsInSync(specIngress.GetAnnotations(), clusterIngress.GetAnnotations()) {
+				log.V(1).Info("Updating ingress", "name", clusterIngress.GetName())
+				// Update ingress's spec and annotations
+				clusterIngress.SetAnnotations(mergeAnnotations(specIngress.GetAnnotations(), clusterIngress.GetAnnotations()))
+				clusterIngress.Object["spec"] = specIngress.Object["spec"]
+				err := r.client.Update(context.TODO(), clusterIngress)
+				if err != nil {
+					return false, err
+				}
+				ingressesInSync = false
+			}
+		} else {
+			err := r.client.Create(context.TODO(), specIngress)
+			if err != nil {
+				return false, err
+			}
+			ingressesInSync = false
+		}
+	}
+
+	return ingressesInSync, nil
+}
+
+// getClusterIngressesV1 lists the routing's ingresses. Unstructured objects are read directly from the API server.
+func (r *ReconcileWorkspaceRouting) getClusterIngressesV1(routing *v1alpha1.WorkspaceRouting) ([]*unstructured.Unstructured, error) {
+	found := &unstructured.UnstructuredList{}
+	found.SetGroupVersionKind(ingressV1GVK.GroupVersion().WithKind("IngressList"))
+	labelSelector, err := labels.Parse(fmt.Sprintf("app=%s", routing.Spec.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	listOptions := &client.ListOptions{
+		Namespace:     routing.Namespace,
+		LabelSelector: labelSelector,
+	}
+	err = r.apiReader.List(context.TODO(), found, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	var ingresses []*unstructured.Unstructured
+	for idx := range found.Items {
+		ingresses = append(ingresses, &found.Items[idx])
+	}
+	return ingresses, nil
+}
+
+// convertIngressToV1 converts an extensions/v1beta1 Ingress to a networking.k8s.io/v1 Ingress with the given ingress
+// class. Metadata, including owner references, is copied over.
+func convertIngressToV1(ingress v1beta1.Ingress, ingressClass string) *unstructured.Unstructured {
+	var rules []interface{}
+	for _, rule := range ingress.Spec.Rules {
+		v1Rule := map[string]interface{}{}
+		if rule.Host != "" {
+			v1Rule["host"] = rule.Host
+		}
+		if rule.HTTP != nil {
+			var paths []interface{}
+			for _, path := range rule.HTTP.Paths {
+				pathValue := path.Path
+				if pathValue == "" {
+					pathValue = "/"
+				}
+				paths = append(paths, map[string]interface{}{
+					"path":     pathValue,
+					"pathType": getPathType(pathValue),
+					"backend":  convertIngressBackendToV1(path.Backend),
+				})
+			}
+			v1Rule["http"] = map[string]interface{}{
+				"paths": paths,
+			}
+		}
+		rules = append(rules, v1Rule)
+	}
+
+	spec := map[string]interface{}{
+		"ingressClassName": ingressClass,
+		"rules":            rules,
+	}
+	if len(ingress.Spec.TLS) > 0 {
+		var tls []interface{}
+		for _, ingressTLS := range ingress.Spec.TLS {
+			v1TLS := map[string]interface{}{}
+			if len(ingressTLS.Hosts) > 0 {
+				var hosts []interface{}
+				for _, host := range ingressTLS.Hosts {
+					hosts = append(hosts, host)
+				}
+				v1TLS["hosts"] = hosts
+			}
+			if ingressTLS.SecretName != "" {
+				v1TLS["secretName"] = ingressTLS.SecretName
+			}
+			tls = append(tls, v1TLS)
+		}
+		spec["tls"] = tls
+	}
+
+	v1Ingress := newIngressV1()
+	v1Ingress.SetName(ingress.Name)
+	v1Ingress.SetNamespace(ingress.Namespace)
+	v1Ingress.SetLabels(ingress.Labels)
+	v1Ingress.SetAnnotations(ingress.Annotations)
+	v1Ingress.SetOwnerReferences(ingress.OwnerReferences)
+	v1Ingress.Object["spec"] = spec
+	return v1Ingress
+}
+
+func convertIngressBackendToV1(backend v1beta1.IngressBackend) map[string]interface{} {
+	port := map[string]interface{}{}
+	if backend.ServicePort.Type == intstr.Int {
+		port["number"] = int64(backend.ServicePort.IntVal)
+	} else {
+		port["name"] = backend.ServicePort.StrVal
+	}
+	return map[string]interface{}{
+		"service": map[string]interface{}{
+			"name": backend.ServiceName,
+			"port": port,
+		},
+	}
+}
+
+// getPathType returns the path type for an ingress path. Paths containing regular expressions are only supported by
+// some ingress controllers and must use the ImplementationSpecific path type.
+func getPathType(path string) string {
+	if strings.ContainsAny(path, "()[]*+?^$|\\") {
+		return pathTypeImplementationSpecific
+	}
+	return pathTypePrefix
+}
+
+func newIngressV1() *unstructured.Unstructured {
+	ingress := &unstructured.Unstructured{}
+	ingress.SetGroupVersionKind(ingressV1GVK)
+	return ingress
+}
+
+func getIngressesV1ToDelete(clusterIngresses, specIngresses []*unstructured.Unstructured) []*unstructured.Unstructured {
+	var toDelete []*unstructured.Unstructured
+	for _, clusterIngress := range clusterIngresses {
+		if contains, _ := listContainsIngressV1ByName(clusterIngress, specIngresses); !contains {
+			toDelete = append(toDelete, clusterIngress)
+		}
+	}
+	return toDelete
+}
+
+func listContainsIngressV1ByName(query *unstructured.Unstructured, list []*unstructured.Unstructured) (exists bool, idx int) {
+	for idx, listIngress := range list {
+		if query.GetName() == listIngress.GetName() {
+			return true, idx
+		}
+	}
+	return false, -1
+}
@@ -0,0 +1,131 @@
+package workspacerouting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspacerouting/solvers"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var istioGVKs = []schema.GroupVersionKind{
+	solvers.IstioGatewayGVK,
+	solvers.IstioVirtualServiceGVK,
+	solvers.IstioAuthorizationPolicyGVK,
+}
+
+// syncIstioObjects syncs the Istio objects of a routing to the cluster. Objects are compared by kind and name.
+func (r *ReconcileWorkspaceRouting) syncIstioObjects(routing *v1alpha1.WorkspaceRouting, specObjects []unstructured.Unstructured) (ok bool, err error) {
+	if !r.istioInstalled {
+		if len(specObjects) > 0 {
+			return false, errors.New("routing requires Istio, but Istio is not installed on the cluster")
+		}
+		return true, nil
+	}
+
+	objectsInSync := true
+	for _, gvk := range istioGVKs {
+		var specObjectsOfKind []unstructured.Unstructured
+		for _, specObject := range specObjects {
+			if specObject.GroupVersionKind() == gvk {
+				specObjectsOfKind = append(specObjectsOfKind, specObject)
+			}
+		}
+		inSync, err := r.syncIstioObjectsOfKind(routing, gvk, specObjectsOfKind)
+		if err != nil {
+			return false, err
+		}
+		objectsInSync = objectsInSync && inSync
+	}
+	return objectsInSync, nil
+}
+
+func (r *ReconcileWorkspaceRouting) syncIstioObjectsOfKind(
+	routing *v1alpha1.WorkspaceRouting,
+	gvk schema.GroupVersionKind,
+	specObjects []unstructured.Unstructured) (ok bool, err error) {
+
+	objectsInSync := true
+
+	clusterObjects, err := r.getClusterIstioObjects(routing, gvk)
+	if err != nil {
+		return false, err
+	}
+
+	toDelete := getIstioObjectsToDelete(clusterObjects, specObjects)
+	for _, obj := range toDelete {
+		err := r.client.Delete(context.TODO(), &obj)
+		if err != nil {
+			return false, err
+		}
+		objectsInSync = false
+	}
+
+	for _, specObject := range specObjects {
+		if contains, idx := listContainsIstioObjectByName(specObject, clusterObjects); contains {
+			clusterObject := clusterObjects[idx]
+			if !equality.Semantic.DeepEqual(specObject.Object["spec"], clusterObject.Object["spec"]) {
+				// Update object's spec
+				clusterObject.Object["spec"] = specObject.Object["spec"]
+				err := r.client.Update(context.TODO(), &clusterObject)
+				if err != nil {
+					return false, err
+				}
+				objectsInSync = false
+			}
+		} else {
+			err := r.client.Create(context.TODO(), &specObject)
+			if err != nil {
+				return false, err
+			}
+			objectsInSync = false
+		}
+	}
+
+	return objectsInSync, nil
+}
+
+// getClusterIstioObjects lists the routing's objects of the given kind. Unstructured objects are read directly from
+// the API server.
+func (r *ReconcileWorkspaceRouting) getClusterIstioObjects(routing *v1alpha1.WorkspaceRouting, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	found := &unstructured.UnstructuredList{}
+	found.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	labelSelector, err := labels.Parse(fmt.Sprintf("app=%s", routing.Spec.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	listOptions := &client.ListOptions{
+		Namespace:     routing.Namespace,
+		LabelSelector: labelSelector,
+	}
+	err = r.apiReader.List(context.TODO(), found, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return found.Items, nil
+}
+
+func getIstioObjectsToDelete(clusterObjects, specObjects []unstructured.Unstructured) []unstructured.Unstructured {
+	var toDelete []unstructured.Unstructured
+	for _, clusterObject := range clusterObjects {
+		if contains, _ := listContainsIstioObjectByName(clusterObject, specObjects); !contains {
+			toDelete = append(toDelete, clusterObject)
+		}
+	}
+	return toDelete
+}
+
+func listContainsIstioObjectByName(query unstructured.Unstructured, list []unstructured.Unstructured) (exists bool, idx int) {
+	for idx, listObject := range list {
+		if query.GetName() == listObject.GetName() {
+			return true, idx
+		}
+	}
+	return false, -1
+}
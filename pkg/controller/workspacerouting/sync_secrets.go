@@ -0,0 +1,100 @@
+package workspacerouting
+
+import (
+	"context"
+	"fmt"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncSecrets creates secrets that do not exist yet and removes secrets that are no longer needed. As secret contents
+// may be randomly generated, existing secrets are only updated if their rotation annotation differs from the spec.
+func (r *ReconcileWorkspaceRouting) syncSecrets(routing *v1alpha1.WorkspaceRouting, specSecrets []corev1.Secret) (ok bool, err error) {
+	secretsInSync := true
+
+	clusterSecrets, err := r.getClusterSecrets(routing)
+	if err != nil {
+		return false, err
+	}
+
+	toDelete := getSecretsToDelete(clusterSecrets, specSecrets)
+	for _, secret := range toDelete {
+		err := r.client.Delete(context.TODO(), &secret)
+		if err != nil {
+			return false, err
+		}
+		secretsInSync = false
+	}
+
+	for _, specSecret := range specSecrets {
+		if contains, idx := listContainsSecretByName(specSecret, clusterSecrets); contains {
+			clusterSecret := clusterSecrets[idx]
+			specRotation := specSecret.Annotations[config.WorkspaceCookieSecretRotationAnnotation]
+			if clusterSecret.Annotations[config.WorkspaceCookieSecretRotationAnnotation] != specRotation {
+				log.Info("Rotating secret", "name", clusterSecret.Name)
+				clusterSecret.Annotations = specSecret.Annotations
+				clusterSecret.Data = specSecret.Data
+				err := r.client.Update(context.TODO(), &clusterSecret)
+				if err != nil {
+					return false, err
+				}
+				secretsInSync = false
+			}
+		} else {
+			err := r.client.Create(context.TODO(), &specSecret)
+			if err != nil {
+				return false, err
+			}
+			secretsInSync = false
+		}
+	}
+
+	return secretsInSync, nil
+}
+
+func (r *ReconcileWorkspaceRouting) getClusterSecrets(routing *v1alpha1.WorkspaceRouting) ([]corev1.Secret, error) {
+	found := &corev1.SecretList{}
+	labelSelector, err := labels.Parse(fmt.Sprintf("app=%s", routing.Spec.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	listOptions := &client.ListOptions{
+		Namespace:     routing.Namespace,
+		LabelSelector: labelSelector,
+	}
+	err = r.client.List(context.TODO(), found, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	// Secrets from e.g. kubernetes component recipes may share labels with workspace secrets; only consider our own
+	var secrets []corev1.Secret
+	for _, secret := range found.Items {
+		if metav1.IsControlledBy(&secret, routing) {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, nil
+}
+
+func getSecretsToDelete(clusterSecrets, specSecrets []corev1.Secret) []corev1.Secret {
+	var toDelete []corev1.Secret
+	for _, clusterSecret := range clusterSecrets {
+		if contains, _ := listContainsSecretByName(clusterSecret, specSecrets); !contains {
+			toDelete = append(toDelete, clusterSecret)
+		}
+	}
+	return toDelete
+}
+
+func listContainsSecretByName(query corev1.Secret, list []corev1.Secret) (exists bool, idx int) {
+	for idx, listSecret := range list {
+		if query.Name == listSecret.Name {
+			return true, idx
+		}
+	}
+	return false, -1
+}
@@ -7,33 +7,43 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
+	"time"
 )
 
+// serviceConflictRetryInterval is how often services that conflict with existing services are retried
+const serviceConflictRetryInterval = time.Minute
+
 var serviceDiffOpts = cmp.Options{
 	cmpopts.IgnoreFields(corev1.Service{}, "TypeMeta", "ObjectMeta", "Status"),
-	cmpopts.IgnoreFields(corev1.ServiceSpec{}, "ClusterIP", "SessionAffinity"),
-	cmpopts.IgnoreFields(corev1.ServicePort{}, "TargetPort"),
+	cmpopts.IgnoreFields(corev1.ServiceSpec{}, "ClusterIP", "SessionAffinity", "ExternalTrafficPolicy", "HealthCheckNodePort"),
+	cmpopts.IgnoreFields(corev1.ServicePort{}, "TargetPort", "NodePort"),
 	cmpopts.SortSlices(func(a, b corev1.ServicePort) bool {
 		return strings.Compare(a.Name, b.Name) > 0
 	}),
 }
 
-func (r *ReconcileWorkspaceRouting) syncServices(routing *v1alpha1.WorkspaceRouting, specServices []corev1.Service) (ok bool, err error) {
+// syncServices creates, updates and deletes the routing's services to match specServices. Services that cannot be
+// created because a service with the same name exists and is not owned by the routing, e.g. a discoverable service of
+// another workspace in the namespace, are not retried and are returned as conflicts.
+func (r *ReconcileWorkspaceRouting) syncServices(routing *v1alpha1.WorkspaceRouting, specServices []corev1.Service) (ok bool, conflicts []string, err error) {
 	servicesInSync := true
 
 	clusterServices, err := r.getClusterServices(routing)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
 	toDelete := getServicesToDelete(clusterServices, specServices)
 	for _, service := range toDelete {
 		err := r.client.Delete(context.TODO(), &service)
 		if err != nil {
-			return false, err
+			return false, nil, err
 		}
 		servicesInSync = false
 	}
@@ -50,20 +60,44 @@ func (r *ReconcileWorkspaceRouting) syncServices(routing *v1alpha1.WorkspaceRout
 				patch := client.MergeFrom(&specService)
 				err := r.client.Patch(context.TODO(), &clusterService, patch)
 				if err != nil {
-					return false, err
+					return false, nil, err
 				}
 				servicesInSync = false
 			}
 		} else {
 			err := r.client.Create(context.TODO(), &specService)
 			if err != nil {
-				return false, err
+				if !errors.IsAlreadyExists(err) {
+					return false, nil, err
+				}
+				conflict, err := r.isServiceConflict(routing, specService.Name)
+				if err != nil {
+					return false, nil, err
+				}
+				if conflict {
+					conflicts = append(conflicts, specService.Name)
+					continue
+				}
 			}
 			servicesInSync = false
 		}
 	}
 
-	return servicesInSync, nil
+	return servicesInSync, conflicts, nil
+}
+
+// isServiceConflict returns true if the service named name exists and is not controlled by routing. Services that
+// are controlled by routing but missing from the cache, e.g. as they were just created, are not conflicts.
+func (r *ReconcileWorkspaceRouting) isServiceConflict(routing *v1alpha1.WorkspaceRouting, name string) (bool, error) {
+	service := &corev1.Service{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: routing.Namespace}, service)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !metav1.IsControlledBy(service, routing), nil
 }
 
 func (r *ReconcileWorkspaceRouting) getClusterServices(routing *v1alpha1.WorkspaceRouting) ([]corev1.Service, error) {
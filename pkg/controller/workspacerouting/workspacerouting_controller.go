@@ -2,15 +2,16 @@ package workspacerouting
 
 import (
 	"context"
-	"fmt"
 	"github.com/che-incubator/che-workspace-operator/internal/cluster"
 	workspacev1alpha1 "github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
 	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspacerouting/solvers"
 	"github.com/google/go-cmp/cmp"
 	routeV1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -31,12 +32,12 @@ func Add(mgr manager.Manager) error {
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileWorkspaceRouting{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) *ReconcileWorkspaceRouting {
+	return &ReconcileWorkspaceRouting{client: mgr.GetClient(), apiReader: mgr.GetAPIReader(), scheme: mgr.GetScheme()}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileWorkspaceRouting) error {
 	// Create a new controller
 	c, err := controller.New("workspacerouting-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -49,7 +50,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch for changes to secondary resources: Services, Ingresses, and (on OpenShift) Routes.
+	// Watch for changes to secondary resources: Services, Ingresses, Secrets, ConfigMaps, Istio objects (if Istio is
+	// installed) and (on OpenShift) Routes.
 	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &workspacev1alpha1.WorkspaceRouting{},
@@ -57,13 +59,58 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
-	err = c.Watch(&source.Kind{Type: &v1beta1.Ingress{}}, &handler.EnqueueRequestForOwner{
+	// Clusters that serve networking.k8s.io/v1 Ingresses may no longer serve the extensions/v1beta1 API
+	ingressV1, err := cluster.IsIngressV1Supported()
+	if err != nil {
+		log.Error(err, "Failed to determine supported Ingress API version")
+		return err
+	}
+	r.ingressV1 = ingressV1
+	var ingressType runtime.Object = &v1beta1.Ingress{}
+	if ingressV1 {
+		ingressType = newIngressV1()
+	}
+	err = c.Watch(&source.Kind{Type: ingressType}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &workspacev1alpha1.WorkspaceRouting{},
 	})
 	if err != nil {
 		return err
 	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &workspacev1alpha1.WorkspaceRouting{},
+	})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &workspacev1alpha1.WorkspaceRouting{},
+	})
+	if err != nil {
+		return err
+	}
+
+	istioInstalled, err := cluster.IsIstioInstalled()
+	if err != nil {
+		log.Error(err, "Failed to determine if Istio is installed")
+		return err
+	}
+	r.istioInstalled = istioInstalled
+	if istioInstalled {
+		for _, gvk := range istioGVKs {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			err = c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForOwner{
+				IsController: true,
+				OwnerType:    &workspacev1alpha1.WorkspaceRouting{},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
 
 	isOpenShift, err := cluster.IsOpenShift()
 	if err != nil {
@@ -91,7 +138,13 @@ type ReconcileWorkspaceRouting struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
-	scheme *runtime.Scheme
+	// apiReader reads objects directly from the apiserver, bypassing the cache
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	// ingressV1 is true if ingresses are created using the networking.k8s.io/v1 API
+	ingressV1 bool
+	// istioInstalled is true if the cluster serves Istio's networking and security APIs
+	istioInstalled bool
 }
 
 // Reconcile reads that state of the cluster for a WorkspaceRouting object and makes changes based on the state read
@@ -114,20 +167,33 @@ func (r *ReconcileWorkspaceRouting) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, err
 	}
 
-	workspaceMeta := solvers.WorkspaceMetadata{
-		WorkspaceId:         instance.Spec.WorkspaceId,
-		Namespace:           instance.Namespace,
-		PodSelector:         instance.Spec.PodSelector,
-		IngressGlobalDomain: instance.Spec.IngressGlobalDomain,
+	solver, ok := solvers.GetSolver(instance.Spec.RoutingClass)
+	if !ok {
+		reqLogger.V(1).Info("No solver registered for routing class; leaving routing to external controllers", "routingClass", instance.Spec.RoutingClass)
+		return reconcile.Result{}, nil
 	}
 
-	solver, err := getSolverForRoutingClass(instance.Spec.RoutingClass)
+	allowedUsers, err := r.getAllowedUsers(instance, reqLogger)
 	if err != nil {
-		// TODO: This is a failure state that should be propagated
 		return reconcile.Result{}, err
 	}
 
-	routingObjects := solver.GetSpecObjects(instance.Spec, workspaceMeta)
+	workspaceMeta := solvers.WorkspaceMetadata{
+		WorkspaceId:          instance.Spec.WorkspaceId,
+		Namespace:            instance.Namespace,
+		PodSelector:          instance.Spec.PodSelector,
+		IngressGlobalDomain:  instance.Spec.IngressGlobalDomain,
+		RoutingClass:         instance.Spec.RoutingClass,
+		CookieSecretRotation: instance.Annotations[config.WorkspaceCookieSecretRotationAnnotation],
+		Creator:              instance.Spec.Creator,
+		AllowedUsers:         allowedUsers,
+		NodeAddress:          config.ControllerCfg.GetRoutingExternalServiceNodeAddress(),
+	}
+
+	routingObjects, err := solver.GetSpecObjects(instance.Spec, workspaceMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	services := routingObjects.Services
 	for idx := range services {
 		err := controllerutil.SetControllerReference(instance, &services[idx], r.scheme)
@@ -149,14 +215,54 @@ func (r *ReconcileWorkspaceRouting) Reconcile(request reconcile.Request) (reconc
 			return reconcile.Result{}, err
 		}
 	}
+	secrets := routingObjects.Secrets
+	for idx := range secrets {
+		err := controllerutil.SetControllerReference(instance, &secrets[idx], r.scheme)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	istioObjects := routingObjects.IstioObjects
+	for idx := range istioObjects {
+		err := controllerutil.SetControllerReference(instance, &istioObjects[idx], r.scheme)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	configMaps := routingObjects.ConfigMaps
+	for idx := range configMaps {
+		err := controllerutil.SetControllerReference(instance, &configMaps[idx], r.scheme)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 
-	servicesInSync, err := r.syncServices(instance, services)
+	secretsInSync, err := r.syncSecrets(instance, secrets)
+	if err != nil || !secretsInSync {
+		reqLogger.Info("Secrets not in sync")
+		return reconcile.Result{Requeue: true}, err
+	}
+
+	configMapsInSync, err := r.syncConfigMaps(instance, configMaps)
+	if err != nil || !configMapsInSync {
+		reqLogger.Info("ConfigMaps not in sync")
+		return reconcile.Result{Requeue: true}, err
+	}
+
+	servicesInSync, serviceConflicts, err := r.syncServices(instance, services)
 	if err != nil || !servicesInSync {
 		reqLogger.Info("Services not in sync")
 		return reconcile.Result{Requeue: true}, err
 	}
 
-	ingressesInSync, err := r.syncIngresses(instance, ingresses)
+	var ingressesInSync bool
+	if r.ingressV1 {
+		ingressesInSync, err = r.syncIngressesV1(instance, ingresses)
+	} else {
+		ingressesInSync, err = r.syncIngresses(instance, ingresses)
+	}
 	if err != nil || !ingressesInSync {
 		reqLogger.Info("Ingresses not in sync")
 		return reconcile.Result{Requeue: true}, err
@@ -168,26 +274,55 @@ func (r *ReconcileWorkspaceRouting) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{Requeue: true}, err
 	}
 
-	return reconcile.Result{}, r.reconcileStatus(instance, routingObjects)
+	istioObjectsInSync, err := r.syncIstioObjects(instance, istioObjects)
+	if err != nil || !istioObjectsInSync {
+		reqLogger.Info("Istio objects not in sync")
+		return reconcile.Result{Requeue: true}, err
+	}
+
+	clusterServices, err := r.getClusterServices(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	solvers.ResolveExternalEndpointURLs(routingObjects.ExposedEndpoints, clusterServices, workspaceMeta)
+
+	endpointStatuses, err := r.getEndpointStatuses(instance, routingObjects)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	addServiceConflictStatuses(endpointStatuses, instance.Spec.Endpoints, serviceConflicts)
+
+	err = r.reconcileStatus(instance, routingObjects, endpointStatuses)
+	if err == nil && len(serviceConflicts) > 0 {
+		// Conflicting services are not owned by the routing, so their removal does not trigger a reconcile
+		reqLogger.Info("Services conflict with existing services", "services", serviceConflicts)
+		return reconcile.Result{RequeueAfter: serviceConflictRetryInterval}, nil
+	}
+	// Changes to the status of services, ingresses and routes trigger a new reconcile, so there is no need to requeue
+	// while waiting for endpoints to become ready
+	return reconcile.Result{}, err
 }
 
-func (r *ReconcileWorkspaceRouting) reconcileStatus(instance *workspacev1alpha1.WorkspaceRouting, routingObjects solvers.RoutingObjects) error {
-	if instance.Status.Ready && cmp.Equal(instance.Status.PodAdditions, routingObjects.PodAdditions) && cmp.Equal(instance.Status.ExposedEndpoints, routingObjects.ExposedEndpoints) {
+func (r *ReconcileWorkspaceRouting) reconcileStatus(
+	instance *workspacev1alpha1.WorkspaceRouting,
+	routingObjects solvers.RoutingObjects,
+	endpointStatuses map[string]workspacev1alpha1.EndpointStatus) error {
+
+	ready := true
+	for _, status := range endpointStatuses {
+		if !status.Ready {
+			ready = false
+		}
+	}
+	if instance.Status.Ready == ready &&
+		cmp.Equal(instance.Status.PodAdditions, routingObjects.PodAdditions) &&
+		cmp.Equal(instance.Status.ExposedEndpoints, routingObjects.ExposedEndpoints) &&
+		cmp.Equal(instance.Status.EndpointStatuses, endpointStatuses) {
 		return nil
 	}
-	instance.Status.Ready = true
+	instance.Status.Ready = ready
 	instance.Status.PodAdditions = routingObjects.PodAdditions
 	instance.Status.ExposedEndpoints = routingObjects.ExposedEndpoints
+	instance.Status.EndpointStatuses = endpointStatuses
 	return r.client.Status().Update(context.TODO(), instance)
 }
-
-func getSolverForRoutingClass(routingClass workspacev1alpha1.WorkspaceRoutingClass) (solvers.RoutingSolver, error) {
-	switch routingClass {
-	case workspacev1alpha1.WorkspaceRoutingDefault:
-		return &solvers.BasicSolver{}, nil
-	case workspacev1alpha1.WorkspaceRoutingOpenShiftOauth:
-		return &solvers.OpenShiftOAuthSolver{}, nil
-	default:
-		return nil, fmt.Errorf("routing class %s not supported", routingClass)
-	}
-}
@@ -0,0 +1,117 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WebhookServerPort is the port the manager's webhook server listens on
+	WebhookServerPort = 8443
+	// WebhookServerCertDir is where the serving certificate secret is mounted in the operator's deployment
+	WebhookServerCertDir = "/tmp/k8s-webhook-server/serving-certs"
+	// WebhookServerServiceName is the name of the Service that exposes the webhook server to the API server
+	WebhookServerServiceName = "che-workspace-operator-webhook"
+	// WebhookServerCertSecretName is the secret holding the webhook server's certificate. On OpenShift it is
+	// generated by the service serving certificate signer.
+	WebhookServerCertSecretName = "che-workspace-operator-webhook-cert"
+
+	webhookServerServicePort = 443
+	caCertFile               = "ca.crt"
+	tlsCertFile              = "tls.crt"
+	tlsKeyFile               = "tls.key"
+
+	openShiftServingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// OpenShiftInjectCABundleAnnotation makes OpenShift fill the caBundle of webhook configurations
+	OpenShiftInjectCABundleAnnotation = "service.beta.openshift.io/inject-cabundle"
+)
+
+// IsCertificateAvailable returns true if the webhook server's serving certificate is mounted. The webhook server
+// cannot start without it.
+func IsCertificateAvailable() bool {
+	for _, file := range []string{tlsCertFile, tlsKeyFile} {
+		if _, err := os.Stat(filepath.Join(WebhookServerCertDir, file)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// GetCABundle returns the CA certificate that signed the webhook server's certificate, if it is provided alongside
+// the certificate. Returns nil if no CA is mounted.
+func GetCABundle() ([]byte, error) {
+	caBundle, err := ioutil.ReadFile(filepath.Join(WebhookServerCertDir, caCertFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return caBundle, nil
+}
+
+// SyncService ensures the Service exposing the webhook server exists in the operator's namespace.
+func SyncService(k8sClient client.Client, namespace string, isOpenShift bool) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebhookServerServiceName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "che-workspace-operator",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "webhook-server",
+					Port:       webhookServerServicePort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(WebhookServerPort),
+				},
+			},
+			Selector: map[string]string{
+				"name": "che-workspace-operator",
+			},
+		},
+	}
+	if isOpenShift {
+		service.Annotations = map[string]string{
+			openShiftServingCertAnnotation: WebhookServerCertSecretName,
+		}
+	}
+
+	clusterService := &corev1.Service{}
+	err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: service.Name}, clusterService)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return k8sClient.Create(context.TODO(), service)
+		}
+		return err
+	}
+
+	clusterService.Labels = service.Labels
+	clusterService.Annotations = service.Annotations
+	clusterService.Spec.Ports = service.Spec.Ports
+	clusterService.Spec.Selector = service.Spec.Selector
+	return k8sClient.Update(context.TODO(), clusterService)
+}
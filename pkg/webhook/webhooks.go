@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/che-incubator/che-workspace-operator/internal/cluster"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/che-incubator/che-workspace-operator/pkg/webhook/server"
+	"github.com/che-incubator/che-workspace-operator/pkg/webhook/workspace"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// SetUpWebhooks registers the operator's admission webhooks with the manager's webhook server and creates the
+// cluster objects required for the API server to call them. Webhooks are skipped if they are disabled in the
+// controller config, not supported by the cluster, or if the webhook server's certificate is not available. Outside of
+// OpenShift, the CA that signed the certificate must be available as well.
+func SetUpWebhooks(mgr manager.Manager) error {
+	if config.ControllerCfg.GetWebhooksEnabled() != "true" {
+		log.Info("Webhooks are disabled in controller config")
+		return nil
+	}
+	enabled, err := cluster.IsWebhookConfigurationEnabled()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		log.Info("Webhooks are not supported by the cluster; skipping")
+		return nil
+	}
+	namespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		if err == k8sutil.ErrRunLocal || err == k8sutil.ErrNoNamespace {
+			log.Info("Webhooks are not supported when running the operator locally; skipping")
+			return nil
+		}
+		return err
+	}
+
+	// The manager's client cannot be used before the manager is started
+	nonCachedClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+
+	err = server.SyncService(nonCachedClient, namespace, config.ControllerCfg.IsOpenShift())
+	if err != nil {
+		return err
+	}
+	if !server.IsCertificateAvailable() {
+		log.Info(fmt.Sprintf("Webhook server certificate not found in %s; webhooks will be enabled once secret '%s' is "+
+			"available and the operator is restarted", server.WebhookServerCertDir, server.WebhookServerCertSecretName))
+		return nil
+	}
+	caBundle, err := server.GetCABundle()
+	if err != nil {
+		return err
+	}
+	// Without a CA, the API server can only verify the webhook server's certificate if OpenShift injects its service CA
+	// into the webhook configurations
+	injectCABundle := caBundle == nil
+	if injectCABundle && !config.ControllerCfg.IsOpenShift() {
+		log.Error(fmt.Errorf("no CA certificate in secret '%s'", server.WebhookServerCertSecretName),
+			"Webhook server CA not found; webhooks will not be enabled")
+		return nil
+	}
+
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	mgr.GetWebhookServer().Register(workspace.ValidateWebhookPath, &webhook.Admission{
+		Handler: workspace.NewWorkspaceValidator(decoder, namespace),
+	})
+	mgr.GetWebhookServer().Register(workspace.ExecWebhookPath, &webhook.Admission{
+		Handler: workspace.NewPodExecValidator(nonCachedClient),
+	})
+	mgr.GetWebhookServer().Register(workspace.MutateWebhookPath, &webhook.Admission{
+		Handler: workspace.NewWorkspaceMutator(decoder),
+	})
+
+	validatingConfig := workspace.GetValidatingWebhookConfig(namespace, caBundle)
+	err = syncValidatingWebhookConfig(nonCachedClient, validatingConfig, injectCABundle)
+	if err != nil {
+		return err
+	}
+	mutatingConfig := workspace.GetMutatingWebhookConfig(namespace, caBundle)
+	err = syncMutatingWebhookConfig(nonCachedClient, mutatingConfig, caBundle == nil)
+	if err != nil {
+		return err
+	}
+	log.Info("Webhooks set up")
+	return nil
+}
+
+// syncValidatingWebhookConfig creates or updates the given ValidatingWebhookConfiguration. If injectCABundle is
+// true, the caBundle is expected to be injected by OpenShift, and the existing value is preserved.
+func syncValidatingWebhookConfig(
+	k8sClient client.Client,
+	specConfig *admissionregistrationv1beta1.ValidatingWebhookConfiguration,
+	injectCABundle bool) error {
+
+	if injectCABundle {
+		specConfig.Annotations = map[string]string{
+			server.OpenShiftInjectCABundleAnnotation: "true",
+		}
+	}
+
+	clusterConfig := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	err := k8sClient.Get(context.TODO(), client.ObjectKey{Name: specConfig.Name}, clusterConfig)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			log.Info("Creating validating webhook configuration", "name", specConfig.Name)
+			return k8sClient.Create(context.TODO(), specConfig)
+		}
+		return err
+	}
+
+	if injectCABundle {
+		for idx := range specConfig.Webhooks {
+			for _, clusterWebhook := range clusterConfig.Webhooks {
+				if clusterWebhook.Name == specConfig.Webhooks[idx].Name {
+					specConfig.Webhooks[idx].ClientConfig.CABundle = clusterWebhook.ClientConfig.CABundle
+				}
+			}
+		}
+	}
+	clusterConfig.Labels = specConfig.Labels
+	clusterConfig.Annotations = specConfig.Annotations
+	clusterConfig.Webhooks = specConfig.Webhooks
+	log.Info("Updating validating webhook configuration", "name", specConfig.Name)
+	return k8sClient.Update(context.TODO(), clusterConfig)
+}
+
+// syncMutatingWebhookConfig creates or updates the given MutatingWebhookConfiguration. If injectCABundle is
+// true, the caBundle is expected to be injected by OpenShift, and the existing value is preserved.
+func syncMutatingWebhookConfig(
+	k8sClient client.Client,
+	specConfig *admissionregistrationv1beta1.MutatingWebhookConfiguration,
+	injectCABundle bool) error {
+
+	if injectCABundle {
+		specConfig.Annotations = map[string]string{
+			server.OpenShiftInjectCABundleAnnotation: "true",
+		}
+	}
+
+	clusterConfig := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	err := k8sClient.Get(context.TODO(), client.ObjectKey{Name: specConfig.Name}, clusterConfig)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			log.Info("Creating mutating webhook configuration", "name", specConfig.Name)
+			return k8sClient.Create(context.TODO(), specConfig)
+		}
+		return err
+	}
+
+	if injectCABundle {
+		for idx := range specConfig.Webhooks {
+			for _, clusterWebhook := range clusterConfig.Webhooks {
+				if clusterWebhook.Name == specConfig.Webhooks[idx].Name {
+					specConfig.Webhooks[idx].ClientConfig.CABundle = clusterWebhook.ClientConfig.CABundle
+				}
+			}
+		}
+	}
+	clusterConfig.Labels = specConfig.Labels
+	clusterConfig.Annotations = specConfig.Annotations
+	clusterConfig.Webhooks = specConfig.Webhooks
+	log.Info("Updating mutating webhook configuration", "name", specConfig.Name)
+	return k8sClient.Update(context.TODO(), clusterConfig)
+}
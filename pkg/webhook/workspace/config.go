@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/che-incubator/che-workspace-operator/pkg/webhook/server"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ValidateWebhookConfigName = "che-workspace-operator-validate.webhook"
+	ValidateWebhookPath       = "/validate-workspace"
+	ExecWebhookPath           = "/validate-exec"
+	MutateWebhookConfigName   = "che-workspace-operator-mutate.webhook"
+	MutateWebhookPath         = "/mutate-workspace"
+)
+
+// GetValidatingWebhookConfig returns the ValidatingWebhookConfiguration that routes Workspace create and update
+// requests, as well as pod exec requests, to the operator's webhook server in namespace.
+//
+// Pod exec requests are only routed for namespaces labelled as containing workspaces, so that exec into other pods
+// in the cluster does not depend on the operator. An object selector cannot be used instead, as the object of an exec
+// request is the PodExecOptions, which has no labels.
+func GetValidatingWebhookConfig(namespace string, caBundle []byte) *admissionregistrationv1beta1.ValidatingWebhookConfiguration {
+	failurePolicy := admissionregistrationv1beta1.Fail
+	path := ValidateWebhookPath
+	execPath := ExecWebhookPath
+	return &admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ValidateWebhookConfigName,
+			Labels: map[string]string{
+				"app": "che-workspace-operator",
+			},
+		},
+		Webhooks: []admissionregistrationv1beta1.ValidatingWebhook{
+			{
+				Name:          "validate-workspace.che.eclipse.org",
+				FailurePolicy: &failurePolicy,
+				ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+					Service: &admissionregistrationv1beta1.ServiceReference{
+						Namespace: namespace,
+						Name:      server.WebhookServerServiceName,
+						Path:      &path,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregistrationv1beta1.RuleWithOperations{
+					{
+						Operations: []admissionregistrationv1beta1.OperationType{
+							admissionregistrationv1beta1.Create,
+							admissionregistrationv1beta1.Update,
+						},
+						Rule: admissionregistrationv1beta1.Rule{
+							APIGroups:   []string{v1alpha1.SchemeGroupVersion.Group},
+							APIVersions: []string{v1alpha1.SchemeGroupVersion.Version},
+							Resources:   []string{"workspaces"},
+						},
+					},
+				},
+			},
+			{
+				Name:          "validate-exec.workspace.che.eclipse.org",
+				FailurePolicy: &failurePolicy,
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						config.WorkspaceNamespaceLabel: "true",
+					},
+				},
+				ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+					Service: &admissionregistrationv1beta1.ServiceReference{
+						Namespace: namespace,
+						Name:      server.WebhookServerServiceName,
+						Path:      &execPath,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregistrationv1beta1.RuleWithOperations{
+					{
+						Operations: []admissionregistrationv1beta1.OperationType{
+							admissionregistrationv1beta1.Connect,
+						},
+						Rule: admissionregistrationv1beta1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"pods/exec"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetMutatingWebhookConfig returns the MutatingWebhookConfiguration that routes Workspace create requests to the
+// operator's webhook server in namespace.
+func GetMutatingWebhookConfig(namespace string, caBundle []byte) *admissionregistrationv1beta1.MutatingWebhookConfiguration {
+	failurePolicy := admissionregistrationv1beta1.Fail
+	path := MutateWebhookPath
+	return &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: MutateWebhookConfigName,
+			Labels: map[string]string{
+				"app": "che-workspace-operator",
+			},
+		},
+		Webhooks: []admissionregistrationv1beta1.MutatingWebhook{
+			{
+				Name:          "mutate-workspace.che.eclipse.org",
+				FailurePolicy: &failurePolicy,
+				ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+					Service: &admissionregistrationv1beta1.ServiceReference{
+						Namespace: namespace,
+						Name:      server.WebhookServerServiceName,
+						Path:      &path,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregistrationv1beta1.RuleWithOperations{
+					{
+						Operations: []admissionregistrationv1beta1.OperationType{
+							admissionregistrationv1beta1.Create,
+						},
+						Rule: admissionregistrationv1beta1.Rule{
+							APIGroups:   []string{v1alpha1.SchemeGroupVersion.Group},
+							APIVersions: []string{v1alpha1.SchemeGroupVersion.Version},
+							Resources:   []string{"workspaces"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PodExecValidator denies exec into workspace pods to anyone other than the workspace's creator and the workspace's
+// own service account. Pods that do not belong to a workspace are not affected.
+type PodExecValidator struct {
+	// client reads directly from the API server, as exec requests may target any namespace
+	client client.Client
+}
+
+func NewPodExecValidator(client client.Client) *PodExecValidator {
+	return &PodExecValidator{client: client}
+}
+
+func (v *PodExecValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	err := v.client.Get(ctx, client.ObjectKey{Name: req.Name, Namespace: req.Namespace}, pod)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return admission.Allowed("")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	workspaceId, ok := pod.Labels[config.WorkspaceIDLabel]
+	if !ok {
+		return admission.Allowed("")
+	}
+
+	workspace, err := v.getWorkspaceForPod(ctx, pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if workspace == nil {
+		return admission.Denied(fmt.Sprintf("workspace %s for pod %s not found", workspaceId, pod.Name))
+	}
+
+	username := req.UserInfo.Username
+	creator, hasCreator := workspace.Annotations[config.WorkspaceCreatorAnnotation]
+	if hasCreator && username == creator {
+		return admission.Allowed("")
+	}
+	if username == fmt.Sprintf(serviceAccountUsernameFmt, workspace.Namespace, "che-"+workspaceId) {
+		return admission.Allowed("")
+	}
+	return admission.Denied(fmt.Sprintf("only the creator of workspace %s may exec into its pods", workspace.Name))
+}
+
+// getWorkspaceForPod returns the workspace named by the pod's workspace name label, or nil if there is no such
+// workspace or its ID does not match the pod's workspace ID label.
+func (v *PodExecValidator) getWorkspaceForPod(ctx context.Context, pod *corev1.Pod) (*v1alpha1.Workspace, error) {
+	workspaceName := pod.Labels[config.WorkspaceNameLabel]
+	if workspaceName == "" {
+		return nil, nil
+	}
+	workspace := &v1alpha1.Workspace{}
+	err := v.client.Get(ctx, client.ObjectKey{Name: workspaceName, Namespace: pod.Namespace}, workspace)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if workspace.Status.WorkspaceId != pod.Labels[config.WorkspaceIDLabel] {
+		return nil, nil
+	}
+	return workspace, nil
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	testNamespace     = "test-ns"
+	testWorkspaceName = "test-workspace"
+	testWorkspaceId   = "workspace123"
+	testCreator       = "alice"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core types to scheme: %s", err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add workspace types to scheme: %s", err)
+	}
+	return scheme
+}
+
+func newExecTestPod(labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workspace-pod",
+			Namespace: testNamespace,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestPodExecValidatorHandle(t *testing.T) {
+	workspace := &v1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testWorkspaceName,
+			Namespace:   testNamespace,
+			Annotations: map[string]string{config.WorkspaceCreatorAnnotation: testCreator},
+		},
+		Status: v1alpha1.WorkspaceStatus{WorkspaceId: testWorkspaceId},
+	}
+	workspaceLabels := map[string]string{
+		config.WorkspaceIDLabel:   testWorkspaceId,
+		config.WorkspaceNameLabel: testWorkspaceName,
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		username string
+		allowed  bool
+	}{
+		{
+			name:     "exec by creator",
+			pod:      newExecTestPod(workspaceLabels),
+			username: testCreator,
+			allowed:  true,
+		},
+		{
+			name:     "exec by workspace service account",
+			pod:      newExecTestPod(workspaceLabels),
+			username: "system:serviceaccount:" + testNamespace + ":che-" + testWorkspaceId,
+			allowed:  true,
+		},
+		{
+			name:     "exec by service account of another workspace",
+			pod:      newExecTestPod(workspaceLabels),
+			username: "system:serviceaccount:" + testNamespace + ":che-otherworkspace",
+			allowed:  false,
+		},
+		{
+			name:     "exec by another user",
+			pod:      newExecTestPod(workspaceLabels),
+			username: "bob",
+			allowed:  false,
+		},
+		{
+			name:     "exec into pod without workspace label",
+			pod:      newExecTestPod(map[string]string{"app": "other"}),
+			username: "bob",
+			allowed:  true,
+		},
+		{
+			name:     "exec into pod that does not exist",
+			username: "bob",
+			allowed:  true,
+		},
+		{
+			name: "exec into pod with mismatched workspace ID",
+			pod: newExecTestPod(map[string]string{
+				config.WorkspaceIDLabel:   "otherworkspace",
+				config.WorkspaceNameLabel: testWorkspaceName,
+			}),
+			username: testCreator,
+			allowed:  false,
+		},
+		{
+			name:     "exec into pod without workspace name label",
+			pod:      newExecTestPod(map[string]string{config.WorkspaceIDLabel: testWorkspaceId}),
+			username: testCreator,
+			allowed:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []runtime.Object{workspace.DeepCopy()}
+			if tt.pod != nil {
+				objs = append(objs, tt.pod)
+			}
+			validator := NewPodExecValidator(fake.NewFakeClientWithScheme(newTestScheme(t), objs...))
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Name:      "workspace-pod",
+					Namespace: testNamespace,
+					Operation: admissionv1beta1.Connect,
+					UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+				},
+			}
+			resp := validator.Handle(context.TODO(), req)
+			if resp.Allowed != tt.allowed {
+				t.Errorf("expected allowed to be %t, got %t (result: %v)", tt.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WorkspaceMutator records the user that creates a Workspace in the Workspace's annotations. Any creator
+// annotations set by the user are overwritten, so that the recorded creator can be trusted.
+type WorkspaceMutator struct {
+	decoder *admission.Decoder
+}
+
+func NewWorkspaceMutator(decoder *admission.Decoder) *WorkspaceMutator {
+	return &WorkspaceMutator{decoder: decoder}
+}
+
+func (m *WorkspaceMutator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create {
+		return admission.Allowed("")
+	}
+
+	workspace := &v1alpha1.Workspace{}
+	err := m.decoder.Decode(req, workspace)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[config.WorkspaceCreatorAnnotation] = req.UserInfo.Username
+	if req.UserInfo.UID != "" {
+		workspace.Annotations[config.WorkspaceCreatorUIDAnnotation] = req.UserInfo.UID
+	} else {
+		delete(workspace.Annotations, config.WorkspaceCreatorUIDAnnotation)
+	}
+
+	marshaled, err := json.Marshal(workspace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const annotationsPath = "/metadata/annotations"
+
+func TestWorkspaceMutatorHandle(t *testing.T) {
+	decoder, err := admission.NewDecoder(newTestScheme(t))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %s", err)
+	}
+	mutator := NewWorkspaceMutator(decoder)
+
+	tests := []struct {
+		name                string
+		operation           admissionv1beta1.Operation
+		annotations         map[string]string
+		userInfo            authenticationv1.UserInfo
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:      "create without annotations",
+			operation: admissionv1beta1.Create,
+			userInfo:  authenticationv1.UserInfo{Username: testCreator, UID: "alice-uid"},
+			expectedAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation:    testCreator,
+				config.WorkspaceCreatorUIDAnnotation: "alice-uid",
+			},
+		},
+		{
+			name:        "create with other annotations",
+			operation:   admissionv1beta1.Create,
+			annotations: map[string]string{"example.com/other": "value"},
+			userInfo:    authenticationv1.UserInfo{Username: testCreator, UID: "alice-uid"},
+			expectedAnnotations: map[string]string{
+				"example.com/other":                  "value",
+				config.WorkspaceCreatorAnnotation:    testCreator,
+				config.WorkspaceCreatorUIDAnnotation: "alice-uid",
+			},
+		},
+		{
+			name:      "create with forged creator",
+			operation: admissionv1beta1.Create,
+			annotations: map[string]string{
+				config.WorkspaceCreatorAnnotation:    "bob",
+				config.WorkspaceCreatorUIDAnnotation: "bob-uid",
+			},
+			userInfo: authenticationv1.UserInfo{Username: testCreator, UID: "alice-uid"},
+			expectedAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation:    testCreator,
+				config.WorkspaceCreatorUIDAnnotation: "alice-uid",
+			},
+		},
+		{
+			name:      "create with forged creator UID by user without UID",
+			operation: admissionv1beta1.Create,
+			annotations: map[string]string{
+				config.WorkspaceCreatorUIDAnnotation: "bob-uid",
+			},
+			userInfo: authenticationv1.UserInfo{Username: testCreator},
+			expectedAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation: testCreator,
+			},
+		},
+		{
+			name:      "update is not mutated",
+			operation: admissionv1beta1.Update,
+			annotations: map[string]string{
+				config.WorkspaceCreatorAnnotation: "bob",
+			},
+			userInfo: authenticationv1.UserInfo{Username: testCreator},
+			expectedAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation: "bob",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := newTestWorkspace(true, tt.annotations)
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Name:      testWorkspaceName,
+					Namespace: testNamespace,
+					Operation: tt.operation,
+					UserInfo:  tt.userInfo,
+					Object:    toRawExtension(t, workspace),
+				},
+			}
+			resp := mutator.Handle(context.TODO(), req)
+			if !resp.Allowed {
+				t.Fatalf("expected request to be allowed, got result: %v", resp.Result)
+			}
+			actual, err := applyAnnotationPatches(tt.annotations, resp)
+			if err != nil {
+				t.Fatalf("failed to apply patches: %s", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tt.expectedAnnotations, actual)
+			}
+		})
+	}
+}
+
+// applyAnnotationPatches applies the response's JSON patch operations on the workspace's annotations to a copy of
+// annotations. Returns an error if the response patches anything other than annotations.
+func applyAnnotationPatches(annotations map[string]string, resp admission.Response) (map[string]string, error) {
+	result := map[string]string{}
+	for key, value := range annotations {
+		result[key] = value
+	}
+	for _, patch := range resp.Patches {
+		switch {
+		case patch.Path == annotationsPath && patch.Operation == "add":
+			values, ok := patch.Value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected value for %s: %v", annotationsPath, patch.Value)
+			}
+			result = map[string]string{}
+			for key, value := range values {
+				result[key] = fmt.Sprint(value)
+			}
+		case strings.HasPrefix(patch.Path, annotationsPath+"/"):
+			key := strings.TrimPrefix(patch.Path, annotationsPath+"/")
+			key = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+			switch patch.Operation {
+			case "add", "replace":
+				result[key] = fmt.Sprint(patch.Value)
+			case "remove":
+				delete(result, key)
+			default:
+				return nil, fmt.Errorf("unexpected operation %s on %s", patch.Operation, patch.Path)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected patch %s on %s", patch.Operation, patch.Path)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
@@ -0,0 +1,235 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/adaptor"
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/common"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	"github.com/che-incubator/che-workspace-operator/pkg/controller/workspacerouting/solvers"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// operatorServiceAccountName is the name of the service account the operator runs as
+	operatorServiceAccountName = "che-workspace-operator"
+	// serviceAccountUsernameFmt formats the username of a service account from its namespace and name
+	serviceAccountUsernameFmt = "system:serviceaccount:%s:%s"
+)
+
+// WorkspaceValidator rejects Workspaces with devfiles that the operator would fail to reconcile
+type WorkspaceValidator struct {
+	decoder *admission.Decoder
+	// operatorUsername is the username of the operator's service account, which may set the workspace creator
+	operatorUsername string
+}
+
+// NewWorkspaceValidator returns a validator for Workspaces. operatorNamespace is the namespace the operator runs in.
+func NewWorkspaceValidator(decoder *admission.Decoder, operatorNamespace string) *WorkspaceValidator {
+	return &WorkspaceValidator{
+		decoder:          decoder,
+		operatorUsername: fmt.Sprintf(serviceAccountUsernameFmt, operatorNamespace, operatorServiceAccountName),
+	}
+}
+
+func (v *WorkspaceValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	workspace := &v1alpha1.Workspace{}
+	err := v.decoder.Decode(req, workspace)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var problems []string
+	switch req.Operation {
+	case admissionv1beta1.Create:
+		problems = validateWorkspace(workspace)
+	case admissionv1beta1.Update:
+		oldWorkspace := &v1alpha1.Workspace{}
+		err := v.decoder.DecodeRaw(req.OldObject, oldWorkspace)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Updates that do not change the spec, e.g. removing finalizers or updating annotations, must keep working for
+		// existing workspaces even if they would no longer pass validation after a configuration change
+		switch {
+		case workspace.DeletionTimestamp != nil:
+		case isSpecChanged(oldWorkspace, workspace):
+			problems = validateWorkspace(workspace)
+		default:
+			problems = validateAnnotations(workspace)
+		}
+		if req.UserInfo.Username != v.operatorUsername {
+			problems = append(problems, validateCreatorUpdate(oldWorkspace, workspace, req.UserInfo)...)
+		}
+	}
+	if len(problems) > 0 {
+		return admission.Denied(fmt.Sprintf("invalid workspace: %s", strings.Join(problems, "; ")))
+	}
+	return admission.Allowed("")
+}
+
+// validateWorkspace returns a list of problems found in the workspace's spec. The list is empty if the workspace
+// is valid.
+func validateWorkspace(workspace *v1alpha1.Workspace) []string {
+	var problems []string
+	devfile := workspace.Spec.Devfile
+
+	if _, _, _, err := adaptor.SortComponentsByType(devfile.Components); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	aliases := map[string]bool{}
+	endpointNames := map[string]bool{}
+	for _, component := range devfile.Components {
+		if component.Alias != "" {
+			if aliases[component.Alias] {
+				problems = append(problems, fmt.Sprintf("duplicate component alias '%s'", component.Alias))
+			}
+			aliases[component.Alias] = true
+		}
+		for _, endpoint := range component.Endpoints {
+			if endpointNames[endpoint.Name] {
+				problems = append(problems, fmt.Sprintf("duplicate endpoint name '%s'", endpoint.Name))
+			}
+			endpointNames[endpoint.Name] = true
+			for attribute := range endpoint.Attributes {
+				if !strings.HasPrefix(string(attribute), v1alpha1.INGRESS_ANNOTATION_ENDPOINT_ATTRIBUTE_PREFIX) {
+					continue
+				}
+				annotation := strings.TrimPrefix(string(attribute), v1alpha1.INGRESS_ANNOTATION_ENDPOINT_ATTRIBUTE_PREFIX)
+				if !config.ControllerCfg.IsEndpointIngressAnnotationAllowed(annotation) {
+					problems = append(problems, fmt.Sprintf("endpoint '%s' sets ingress annotation '%s', which is not allowed", endpoint.Name, annotation))
+				}
+			}
+			// Discoverable endpoints are exposed through a service named after the endpoint
+			if endpoint.Attributes[v1alpha1.DISCOVERABLE_ATTRIBUTE] == "true" {
+				for _, msg := range validation.IsDNS1035Label(common.EndpointName(endpoint.Name)) {
+					problems = append(problems, fmt.Sprintf("discoverable endpoint '%s' is not a valid service name: %s", endpoint.Name, msg))
+				}
+			}
+		}
+		if component.MemoryLimit != "" {
+			if _, err := resource.ParseQuantity(component.MemoryLimit); err != nil {
+				problems = append(problems, fmt.Sprintf("invalid memoryLimit '%s' in component '%s'", component.MemoryLimit, getComponentName(component)))
+			}
+		}
+	}
+
+	for _, command := range devfile.Commands {
+		for _, action := range command.Actions {
+			if action.Component != "" && !aliases[action.Component] {
+				problems = append(problems, fmt.Sprintf("command '%s' refers to unknown component '%s'", command.Name, action.Component))
+			}
+		}
+	}
+
+	if storageSize := devfile.DevfileAttributes.StorageSize; storageSize != "" {
+		if _, err := resource.ParseQuantity(storageSize); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid storageSize '%s'", storageSize))
+		}
+	}
+
+	problems = append(problems, validateAnnotations(workspace)...)
+
+	if !isSupportedRoutingClass(workspace.Spec.RoutingClass) {
+		problems = append(problems, fmt.Sprintf("unsupported routingClass '%s'", workspace.Spec.RoutingClass))
+	}
+
+	return problems
+}
+
+// validateAnnotations returns a list of problems found in the workspace's annotations
+func validateAnnotations(workspace *v1alpha1.Workspace) []string {
+	var problems []string
+	for _, annotation := range []string{config.WorkspaceIdleTimeoutAnnotation, config.WorkspaceMaxRunTimeAnnotation} {
+		if value, ok := workspace.Annotations[annotation]; ok && value != "" {
+			if _, err := time.ParseDuration(value); err != nil {
+				problems = append(problems, fmt.Sprintf("invalid duration '%s' in annotation %s", value, annotation))
+			}
+		}
+	}
+	if value, ok := workspace.Annotations[config.WorkspaceLastActivityAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid time '%s' in annotation %s; expected RFC3339 format", value, config.WorkspaceLastActivityAnnotation))
+		}
+	}
+	return problems
+}
+
+// isSpecChanged returns true if the update changes the workspace's spec. Stopping a workspace is not considered a
+// change, so that workspaces can always be stopped.
+func isSpecChanged(oldWorkspace, workspace *v1alpha1.Workspace) bool {
+	oldSpec := oldWorkspace.Spec.DeepCopy()
+	if !workspace.Spec.Started {
+		oldSpec.Started = false
+	}
+	return !equality.Semantic.DeepEqual(*oldSpec, workspace.Spec)
+}
+
+// validateCreatorUpdate checks that the workspace's creator annotations are only changed by the recorded creator.
+// Creator annotations can not be added to workspaces that do not have them, e.g. as they were created while the
+// mutating webhook was unavailable, as that would allow any user with update rights to claim the workspace.
+func validateCreatorUpdate(oldWorkspace, workspace *v1alpha1.Workspace, userInfo authenticationv1.UserInfo) []string {
+	oldCreator, hasCreator := oldWorkspace.Annotations[config.WorkspaceCreatorAnnotation]
+	if hasCreator && userInfo.Username == oldCreator {
+		return nil
+	}
+
+	var problems []string
+	for _, annotation := range []string{config.WorkspaceCreatorAnnotation, config.WorkspaceCreatorUIDAnnotation} {
+		oldValue, oldOk := oldWorkspace.Annotations[annotation]
+		newValue, newOk := workspace.Annotations[annotation]
+		if oldValue == newValue && oldOk == newOk {
+			continue
+		}
+		if hasCreator {
+			problems = append(problems, fmt.Sprintf("annotation %s can only be changed by the workspace creator", annotation))
+		} else {
+			problems = append(problems, fmt.Sprintf("annotation %s can only be set when the workspace is created", annotation))
+		}
+	}
+	return problems
+}
+
+// isSupportedRoutingClass returns true if routingClass has a built-in solver or is configured as reconciled by an
+// external controller
+func isSupportedRoutingClass(routingClass v1alpha1.WorkspaceRoutingClass) bool {
+	if solvers.IsRegistered(routingClass) {
+		return true
+	}
+	for _, externalClass := range config.ControllerCfg.GetRoutingExternalClasses() {
+		if string(routingClass) == externalClass {
+			return true
+		}
+	}
+	return false
+}
+
+func getComponentName(component v1alpha1.ComponentSpec) string {
+	if component.Alias != "" {
+		return component.Alias
+	}
+	return component.Id
+}
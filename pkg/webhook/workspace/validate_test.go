@@ -0,0 +1,243 @@
+//
+// Copyright (c) 2019-2020 Red Hat, Inc.
+// This program and the accompanying materials are made
+// available under the terms of the Eclipse Public License 2.0
+// which is available at https://www.eclipse.org/legal/epl-2.0/
+//
+// SPDX-License-Identifier: EPL-2.0
+//
+// Contributors:
+//   Red Hat, Inc. - initial API and implementation
+//
+
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/che-incubator/che-workspace-operator/pkg/apis/workspace/v1alpha1"
+	"github.com/che-incubator/che-workspace-operator/pkg/config"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newTestWorkspace(started bool, annotations map[string]string) *v1alpha1.Workspace {
+	return &v1alpha1.Workspace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Workspace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testWorkspaceName,
+			Namespace:   testNamespace,
+			Annotations: annotations,
+		},
+		Spec: v1alpha1.WorkspaceSpec{Started: started},
+	}
+}
+
+func toRawExtension(t *testing.T, obj runtime.Object) runtime.RawExtension {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal object: %s", err)
+	}
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestValidateCreatorUpdate(t *testing.T) {
+	creatorAnnotations := map[string]string{
+		config.WorkspaceCreatorAnnotation:    testCreator,
+		config.WorkspaceCreatorUIDAnnotation: "alice-uid",
+	}
+	tests := []struct {
+		name           string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		username       string
+		expectProblems bool
+	}{
+		{
+			name:           "unchanged by non-creator",
+			oldAnnotations: creatorAnnotations,
+			newAnnotations: creatorAnnotations,
+			username:       "bob",
+		},
+		{
+			name:           "changed by creator",
+			oldAnnotations: creatorAnnotations,
+			newAnnotations: map[string]string{config.WorkspaceCreatorAnnotation: "bob"},
+			username:       testCreator,
+		},
+		{
+			name:           "creator changed by non-creator",
+			oldAnnotations: creatorAnnotations,
+			newAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation:    "bob",
+				config.WorkspaceCreatorUIDAnnotation: "alice-uid",
+			},
+			username:       "bob",
+			expectProblems: true,
+		},
+		{
+			name:           "creator UID changed by non-creator",
+			oldAnnotations: creatorAnnotations,
+			newAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation:    testCreator,
+				config.WorkspaceCreatorUIDAnnotation: "bob-uid",
+			},
+			username:       "bob",
+			expectProblems: true,
+		},
+		{
+			name:           "creator removed by non-creator",
+			oldAnnotations: creatorAnnotations,
+			newAnnotations: map[string]string{config.WorkspaceCreatorUIDAnnotation: "alice-uid"},
+			username:       "bob",
+			expectProblems: true,
+		},
+		{
+			name:           "creator added by non-creator",
+			oldAnnotations: nil,
+			newAnnotations: map[string]string{config.WorkspaceCreatorAnnotation: "bob"},
+			username:       "bob",
+			expectProblems: true,
+		},
+		{
+			name:           "other annotation changed by non-creator",
+			oldAnnotations: creatorAnnotations,
+			newAnnotations: map[string]string{
+				config.WorkspaceCreatorAnnotation:    testCreator,
+				config.WorkspaceCreatorUIDAnnotation: "alice-uid",
+				"example.com/other":                  "value",
+			},
+			username: "bob",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldWorkspace := newTestWorkspace(true, tt.oldAnnotations)
+			workspace := newTestWorkspace(true, tt.newAnnotations)
+			problems := validateCreatorUpdate(oldWorkspace, workspace, authenticationv1.UserInfo{Username: tt.username})
+			if tt.expectProblems && len(problems) == 0 {
+				t.Errorf("expected problems, got none")
+			}
+			if !tt.expectProblems && len(problems) > 0 {
+				t.Errorf("expected no problems, got %v", problems)
+			}
+		})
+	}
+}
+
+func TestIsSpecChanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldStarted bool
+		newStarted bool
+		newClass   v1alpha1.WorkspaceRoutingClass
+		expected   bool
+	}{
+		{name: "no change", oldStarted: true, newStarted: true, expected: false},
+		{name: "stop only", oldStarted: true, newStarted: false, expected: false},
+		{name: "start", oldStarted: false, newStarted: true, expected: true},
+		{name: "spec change", oldStarted: true, newStarted: true, newClass: v1alpha1.WorkspaceRoutingOpenShiftOauth, expected: true},
+		{name: "stop with spec change", oldStarted: true, newStarted: false, newClass: v1alpha1.WorkspaceRoutingOpenShiftOauth, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldWorkspace := newTestWorkspace(tt.oldStarted, nil)
+			workspace := newTestWorkspace(tt.newStarted, nil)
+			workspace.Spec.RoutingClass = tt.newClass
+			if actual := isSpecChanged(oldWorkspace, workspace); actual != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidatorHandleUpdate(t *testing.T) {
+	decoder, err := admission.NewDecoder(newTestScheme(t))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %s", err)
+	}
+	validator := NewWorkspaceValidator(decoder, "operator-ns")
+
+	// The old workspace is invalid, e.g. as it was created before validation was enabled
+	oldWorkspace := newTestWorkspace(true, map[string]string{config.WorkspaceCreatorAnnotation: testCreator})
+	oldWorkspace.Spec.Devfile.StorageSize = "invalid"
+
+	tests := []struct {
+		name     string
+		started  bool
+		username string
+		mutate   func(workspace *v1alpha1.Workspace)
+		allowed  bool
+	}{
+		{name: "stop only", started: false, username: testCreator, allowed: true},
+		{name: "stop only by non-creator", started: false, username: "bob", allowed: true},
+		{name: "no change", started: true, username: testCreator, allowed: true},
+		{
+			name:     "spec change",
+			started:  true,
+			username: testCreator,
+			mutate: func(workspace *v1alpha1.Workspace) {
+				workspace.Spec.Devfile.Name = "changed"
+			},
+			allowed: false,
+		},
+		{
+			name:     "spec change that fixes the workspace",
+			started:  true,
+			username: testCreator,
+			mutate: func(workspace *v1alpha1.Workspace) {
+				workspace.Spec.Devfile.StorageSize = "1Gi"
+			},
+			allowed: true,
+		},
+		{
+			name:     "stop and creator change by non-creator",
+			started:  false,
+			username: "bob",
+			mutate: func(workspace *v1alpha1.Workspace) {
+				workspace.Annotations[config.WorkspaceCreatorAnnotation] = "bob"
+			},
+			allowed: false,
+		},
+		{
+			name:     "creator change by operator",
+			started:  true,
+			username: "system:serviceaccount:operator-ns:" + operatorServiceAccountName,
+			mutate: func(workspace *v1alpha1.Workspace) {
+				workspace.Annotations[config.WorkspaceCreatorAnnotation] = "bob"
+			},
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := oldWorkspace.DeepCopy()
+			workspace.Spec.Started = tt.started
+			if tt.mutate != nil {
+				tt.mutate(workspace)
+			}
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Name:      testWorkspaceName,
+					Namespace: testNamespace,
+					Operation: admissionv1beta1.Update,
+					UserInfo:  authenticationv1.UserInfo{Username: tt.username},
+					Object:    toRawExtension(t, workspace),
+					OldObject: toRawExtension(t, oldWorkspace),
+				},
+			}
+			resp := validator.Handle(context.TODO(), req)
+			if resp.Allowed != tt.allowed {
+				t.Errorf("expected allowed to be %t, got %t (result: %v)", tt.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}